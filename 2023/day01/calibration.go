@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+// Policy says what to do with a line that has no digits in it.
+type Policy string
+
+const (
+	Fail Policy = "fail" // fail the part with an error
+	Skip Policy = "skip" // leave the line out of the total
+	Zero Policy = "zero" // count the line as a calibration value of 0
+)
+
+func (p *Policy) Set(s string) error {
+	switch Policy(s) {
+	case Fail, Skip, Zero:
+		*p = Policy(s)
+		return nil
+	}
+	return fmt.Errorf("unknown policy %q, expected fail, skip or zero", s)
+}
+
+func (p *Policy) String() string {
+	return string(*p)
+}
+
+// Calibration is the value recovered from a single line of the document.
+type Calibration struct {
+	Line        int // 1-based line number in the input
+	Text        string
+	First, Last int
+	Value       int
+	Found       bool // false if the line had no digits
+}
+
+// digitFinder returns the first and last digits in a line, or false if there
+// are none.
+type digitFinder func(line string) (first, last int, ok bool)
+
+// calibrate works out the calibration value of every line. Lines without
+// digits are kept, with Found unset and a value of 0, for the caller to deal
+// with according to its policy.
+func calibrate(input string, find digitFinder) []Calibration {
+	result := []Calibration{}
+	for _, line := range utils.Lines(input) {
+		c := Calibration{Line: line.Number, Text: line.Text}
+		c.First, c.Last, c.Found = find(line.Text)
+		if c.Found {
+			c.Value = c.First*10 + c.Last
+		}
+		result = append(result, c)
+	}
+	return result
+}
+
+// applyPolicy returns an error if policy is Fail and any line has no digits.
+// The error points at the first such line and lists the rest.
+func applyPolicy(calibrations []Calibration, policy Policy) error {
+	lines := missing(calibrations)
+	if policy != Fail || len(lines) == 0 {
+		return nil
+	}
+	for _, c := range calibrations {
+		if !c.Found {
+			err := fmt.Errorf("no digits on %d line(s) %v (use -missing skip or zero to carry on)", len(lines), lines)
+			return &utils.ParseError{Line: c.Line, Text: c.Text, Err: err}
+		}
+	}
+	return nil
+}
+
+// sum adds up the calibration values. Lines without digits contribute
+// nothing whether they were skipped or counted as zero.
+func sum(calibrations []Calibration) (total int) {
+	for _, c := range calibrations {
+		total += c.Value
+	}
+	return total
+}
+
+// missing returns the line numbers of lines without digits
+func missing(calibrations []Calibration) []int {
+	lines := []int{}
+	for _, c := range calibrations {
+		if !c.Found {
+			lines = append(lines, c.Line)
+		}
+	}
+	return lines
+}
+
+// writeCSV writes one row per line per part with its calibration value.
+// Lines without digits have empty digit columns, and an empty value unless
+// they were counted as zero.
+func writeCSV(w io.Writer, parts [][]Calibration, policy Policy) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"part", "line", "text", "first", "last", "value"})
+	for i, calibrations := range parts {
+		for _, c := range calibrations {
+			first, last, value := "", "", strconv.Itoa(c.Value)
+			if c.Found {
+				first, last = strconv.Itoa(c.First), strconv.Itoa(c.Last)
+			} else if policy != Zero {
+				value = ""
+			}
+			out.Write([]string{strconv.Itoa(i + 1), strconv.Itoa(c.Line), c.Text, first, last, value})
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
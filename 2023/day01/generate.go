@@ -0,0 +1,36 @@
+package main
+
+import (
+	"math/rand"
+	"strings"
+)
+
+var spelled = []string{"one", "two", "three", "four", "five", "six", "seven", "eight", "nine"}
+
+// generate makes size lines of lowercase letters mixed with digits and
+// spelled out digits, some of them overlapping ("eightwo"). Every line has
+// at least one digit character, as in the real input.
+func generate(rng *rand.Rand, size int) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < size; i++ {
+		pieces := []string{string(rune('1' + rng.Intn(9)))}
+		for n := rng.Intn(6); n > 0; n-- {
+			switch rng.Intn(3) {
+			case 0:
+				pieces = append(pieces, string(rune('1'+rng.Intn(9))))
+			case 1:
+				pieces = append(pieces, spelled[rng.Intn(len(spelled))])
+			default:
+				letters := make([]byte, 1+rng.Intn(5))
+				for j := range letters {
+					letters[j] = byte('a' + rng.Intn(26))
+				}
+				pieces = append(pieces, string(letters))
+			}
+		}
+		rng.Shuffle(len(pieces), func(a, b int) { pieces[a], pieces[b] = pieces[b], pieces[a] })
+		sb.WriteString(strings.Join(pieces, ""))
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+// year and day of the puzzle
+const year, day = 2023, 1
+
+func check(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
+
+// asciiDigits finds the first and last digit characters in a line
+func asciiDigits(line string) (first, last int, ok bool) {
+	first = -1
+	last = -1
+	for _, char := range strings.Split(line, "") {
+		intval, err := strconv.Atoi(char)
+		if err == nil {
+			// it is an int
+			if first == -1 {
+				first = intval
+			}
+			last = intval
+		}
+	}
+	return first, last, first != -1
+}
+
+func part1(calibrations []Calibration) int {
+	check(applyPolicy(calibrations, policy))
+	return sum(calibrations)
+}
+
+var vocabs = flag.String("vocab", "digits,english", "Comma separated vocabularies for part 2: builtin names (digits, english) or files of \"word value\" lines")
+var example = flag.Bool("example", false, "Use example input instead of AoC URL")
+var csvPath = flag.String("csv", "", "Write the calibration value of every line to this CSV file")
+
+// policy is what to do with lines that have no digits, set by -missing
+var policy = Fail
+
+func init() {
+	flag.Var(&policy, "missing", "What to do with lines without digits: fail, skip or zero")
+	utils.FileFlag("vocab")
+}
+
+// digitWords matches the words from -vocab, set in main
+var digitWords *Matcher
+
+// vocabDigits finds the first and last digits in a line, spelled out using
+// words from the vocabulary
+func vocabDigits(line string) (first, last int, ok bool) {
+	firstMatch, ok := digitWords.First(line)
+	if !ok {
+		return -1, -1, false
+	}
+	lastMatch, _ := digitWords.Last(line)
+	return firstDigit(firstMatch.Value), lastDigit(lastMatch.Value), true
+}
+
+func part2(calibrations []Calibration) int {
+	check(applyPolicy(calibrations, policy))
+	return sum(calibrations)
+}
+
+// writeReport lists lines without digits on stderr and, if requested, writes
+// every line's calibration values to a CSV file. It runs whether or not the
+// parts succeeded, since that's when the report is most useful, so problems
+// writing it are logged rather than fatal.
+func writeReport(parts [][]Calibration) {
+	for i, calibrations := range parts {
+		if lines := missing(calibrations); len(lines) > 0 {
+			utils.Log.Warn("lines without digits", "part", i+1, "policy", policy.String(), "lines", lines)
+		}
+	}
+
+	if *csvPath == "" {
+		return
+	}
+	out, err := os.Create(*csvPath)
+	if err != nil {
+		utils.Log.Error("couldn't write CSV report", "error", err)
+		return
+	}
+	defer out.Close()
+	if err := writeCSV(out, parts, policy); err != nil {
+		utils.Log.Error("couldn't write CSV report", "path", *csvPath, "error", err)
+	}
+}
+
+var TEST_INPUT = `1abc2
+pqr3stu8vwx
+a1b2c3d4e5f
+treb7uchet
+`
+
+var TEST_INPUT2 = `two1nine
+eightwothree
+abcone2threexyz
+xtwone3four
+4nineeightseven2
+zoneight234
+7pqrstsixteen
+`
+
+func main() {
+	flag.Parse()
+
+	words, err := loadVocabularies(*vocabs)
+	check(err)
+	digitWords = NewMatcher(words)
+
+	var input, input2 string
+	if *example {
+		input, input2 = TEST_INPUT, TEST_INPUT2
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 1000)
+		input2 = input
+	} else {
+		input = utils.GetInputs(year, day)
+		input2 = input
+	}
+
+	calibrations1 := calibrate(input, asciiDigits)
+	calibrations2 := calibrate(input2, vocabDigits)
+
+	utils.Solve(year, day, 1, input, func(string) int { return part1(calibrations1) })
+	utils.Solve(year, day, 2, input2, func(string) int { return part2(calibrations2) })
+
+	writeReport([][]Calibration{calibrations1, calibrations2})
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"sort"
+)
+
+// Match is an occurrence of a vocabulary word within a line.
+type Match struct {
+	Start int // byte offset of the first character of the word
+	Word  string
+	Value int
+}
+
+// Matcher finds vocabulary words in a line using Aho-Corasick automata, one
+// built from the words and one from the words reversed so the last match in
+// a line can be found by scanning from the end.
+type Matcher struct {
+	forward *automaton
+	reverse *automaton
+}
+
+// NewMatcher builds a Matcher for the given word -> value vocabulary.
+func NewMatcher(vocab map[string]int) *Matcher {
+	// sort so the automata (and therefore match order) don't depend on map order
+	words := make([]string, 0, len(vocab))
+	for word := range vocab {
+		words = append(words, word)
+	}
+	sort.Strings(words)
+
+	reversed := make([]string, len(words))
+	for i, word := range words {
+		reversed[i] = reverse(word)
+	}
+
+	values := make([]int, len(words))
+	for i, word := range words {
+		values[i] = vocab[word]
+	}
+
+	return &Matcher{
+		forward: newAutomaton(words, values),
+		reverse: newAutomaton(reversed, values),
+	}
+}
+
+// All returns every occurrence of every word in line, including overlapping
+// ones (e.g. both "two" and "one" in "twone"), ordered by where they end.
+func (m *Matcher) All(line string) []Match {
+	a := m.forward
+	matches := []Match{}
+	state := 0
+	for i := 0; i < len(line); i++ {
+		state = a.step(state, line[i])
+		for n := a.firstOutput(state); n != -1; n = a.nodes[n].output {
+			word := a.nodes[n].word
+			matches = append(matches, Match{i - len(a.words[word]) + 1, a.words[word], a.values[word]})
+		}
+	}
+	return matches
+}
+
+// First returns the match that starts earliest in line, preferring the
+// longest word when several start at the same place.
+func (m *Matcher) First(line string) (best Match, ok bool) {
+	a := m.forward
+	state := 0
+	for i := 0; i < len(line); i++ {
+		// nothing ending here or later can start at or before the best so far
+		if ok && i-a.maxLen+1 > best.Start {
+			break
+		}
+		state = a.step(state, line[i])
+		for n := a.firstOutput(state); n != -1; n = a.nodes[n].output {
+			word := a.nodes[n].word
+			start := i - len(a.words[word]) + 1
+			if !ok || start < best.Start || (start == best.Start && len(a.words[word]) > len(best.Word)) {
+				best = Match{start, a.words[word], a.values[word]}
+				ok = true
+			}
+		}
+	}
+	return best, ok
+}
+
+// Last returns the match that starts latest in line, preferring the longest
+// word when several start at the same place. It scans line from the end, so
+// it stops as soon as it finds a match rather than walking the whole line.
+func (m *Matcher) Last(line string) (Match, bool) {
+	a := m.reverse
+	state := 0
+	for i := len(line) - 1; i >= 0; i-- {
+		state = a.step(state, line[i])
+		// the first output on the chain is the longest word ending here
+		if n := a.firstOutput(state); n != -1 {
+			word := a.nodes[n].word
+			return Match{i, reverse(a.words[word]), a.values[word]}, true
+		}
+	}
+	return Match{}, false
+}
+
+type node struct {
+	next   map[byte]int
+	fail   int // longest proper suffix of this node that is also a node
+	word   int // index of the word spelled by this node, -1 if none
+	output int // nearest node on the fail chain that spells a word, -1 if none
+}
+
+type automaton struct {
+	nodes  []node
+	words  []string
+	values []int
+	maxLen int
+}
+
+func newAutomaton(words []string, values []int) *automaton {
+	a := &automaton{words: words, values: values}
+	a.nodes = append(a.nodes, node{next: map[byte]int{}, word: -1, output: -1})
+
+	// build the trie
+	for i, word := range words {
+		a.maxLen = max(a.maxLen, len(word))
+		state := 0
+		for j := 0; j < len(word); j++ {
+			next, ok := a.nodes[state].next[word[j]]
+			if !ok {
+				next = len(a.nodes)
+				a.nodes = append(a.nodes, node{next: map[byte]int{}, word: -1, output: -1})
+				a.nodes[state].next[word[j]] = next
+			}
+			state = next
+		}
+		a.nodes[state].word = i
+	}
+
+	// fill in fail and output links breadth first, so a node's fail target is
+	// always complete before the node itself
+	queue := []int{}
+	for _, child := range a.nodes[0].next {
+		queue = append(queue, child)
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for c, child := range a.nodes[state].next {
+			fail := a.step(a.nodes[state].fail, c)
+			a.nodes[child].fail = fail
+			if a.nodes[fail].word != -1 {
+				a.nodes[child].output = fail
+			} else {
+				a.nodes[child].output = a.nodes[fail].output
+			}
+			queue = append(queue, child)
+		}
+	}
+	return a
+}
+
+// step follows the transition for c from state, falling back along fail
+// links when there is no direct edge.
+func (a *automaton) step(state int, c byte) int {
+	for {
+		if next, ok := a.nodes[state].next[c]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = a.nodes[state].fail
+	}
+}
+
+// firstOutput returns state if it spells a word, otherwise the nearest node on
+// its fail chain that does, or -1.
+func (a *automaton) firstOutput(state int) int {
+	if a.nodes[state].word != -1 {
+		return state
+	}
+	return a.nodes[state].output
+}
+
+func reverse(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
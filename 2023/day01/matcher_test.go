@@ -0,0 +1,143 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testVocab has words that overlap ("twone", "oneight") and a word that is
+// a prefix of another ("seven", "seventeen").
+var testVocab = map[string]int{
+	"1": 1, "one": 1, "two": 2, "seven": 7, "eight": 8, "seventeen": 17,
+}
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		line        string
+		all         []Match
+		first, last Match
+	}{
+		{
+			line:  "twone",
+			all:   []Match{{0, "two", 2}, {2, "one", 1}},
+			first: Match{0, "two", 2},
+			last:  Match{2, "one", 1},
+		},
+		{
+			line:  "eightwo",
+			all:   []Match{{0, "eight", 8}, {4, "two", 2}},
+			first: Match{0, "eight", 8},
+			last:  Match{4, "two", 2},
+		},
+		{
+			line:  "oneight",
+			all:   []Match{{0, "one", 1}, {2, "eight", 8}},
+			first: Match{0, "one", 1},
+			last:  Match{2, "eight", 8},
+		},
+		{
+			line:  "xseventeenx",
+			all:   []Match{{1, "seven", 7}, {1, "seventeen", 17}},
+			first: Match{1, "seventeen", 17},
+			last:  Match{1, "seventeen", 17},
+		},
+		{
+			line:  "sevenseventeen",
+			all:   []Match{{0, "seven", 7}, {5, "seven", 7}, {5, "seventeen", 17}},
+			first: Match{0, "seven", 7},
+			last:  Match{5, "seventeen", 17},
+		},
+		{
+			line:  "seventee",
+			all:   []Match{{0, "seven", 7}},
+			first: Match{0, "seven", 7},
+			last:  Match{0, "seven", 7},
+		},
+		{
+			line:  "1two1",
+			all:   []Match{{0, "1", 1}, {1, "two", 2}, {4, "1", 1}},
+			first: Match{0, "1", 1},
+			last:  Match{4, "1", 1},
+		},
+	}
+	m := NewMatcher(testVocab)
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			if got := m.All(tt.line); !reflect.DeepEqual(got, tt.all) {
+				t.Errorf("All = %v, want %v", got, tt.all)
+			}
+			if got, ok := m.First(tt.line); !ok || got != tt.first {
+				t.Errorf("First = %v, %t, want %v", got, ok, tt.first)
+			}
+			if got, ok := m.Last(tt.line); !ok || got != tt.last {
+				t.Errorf("Last = %v, %t, want %v", got, ok, tt.last)
+			}
+		})
+	}
+}
+
+func TestMatcherNoMatch(t *testing.T) {
+	m := NewMatcher(testVocab)
+	for _, line := range []string{"", "abc", "seve", "tw"} {
+		if got := m.All(line); len(got) != 0 {
+			t.Errorf("All(%q) = %v, want no matches", line, got)
+		}
+		if got, ok := m.First(line); ok {
+			t.Errorf("First(%q) = %v, want no match", line, got)
+		}
+		if got, ok := m.Last(line); ok {
+			t.Errorf("Last(%q) = %v, want no match", line, got)
+		}
+	}
+}
+
+// scan finds every match by trying each word at each offset, in the order
+// All reports them: by where they end, longest first.
+func scan(line string) []Match {
+	matches := []Match{}
+	for end := 1; end <= len(line); end++ {
+		for start := 0; start < end; start++ {
+			if value, ok := testVocab[line[start:end]]; ok {
+				matches = append(matches, Match{start, line[start:end], value})
+			}
+		}
+	}
+	return matches
+}
+
+func TestMatcherAgainstScan(t *testing.T) {
+	m := NewMatcher(testVocab)
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		var sb strings.Builder
+		for j := rng.Intn(20); j > 0; j-- {
+			sb.WriteByte("1onetwsvigh"[rng.Intn(11)])
+		}
+		line := sb.String()
+
+		want := scan(line)
+		if got := m.All(line); !reflect.DeepEqual(got, want) {
+			t.Fatalf("All(%q) = %v, want %v", line, got, want)
+		}
+		if len(want) == 0 {
+			continue
+		}
+		first, last := want[0], want[0]
+		for _, match := range want {
+			if match.Start < first.Start || (match.Start == first.Start && len(match.Word) > len(first.Word)) {
+				first = match
+			}
+			if match.Start > last.Start || (match.Start == last.Start && len(match.Word) > len(last.Word)) {
+				last = match
+			}
+		}
+		if got, _ := m.First(line); got != first {
+			t.Fatalf("First(%q) = %v, want %v", line, got, first)
+		}
+		if got, _ := m.Last(line); got != last {
+			t.Fatalf("Last(%q) = %v, want %v", line, got, last)
+		}
+	}
+}
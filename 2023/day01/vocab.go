@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Vocabulary maps the words that may appear in a line to the number they
+// stand for. Values may have several digits (e.g. "eleven" -> 11), in which
+// case the first and last digits of the value are used for calibration.
+type Vocabulary struct {
+	Name  string
+	Words map[string]int
+}
+
+var builtinVocabularies = map[string]map[string]int{
+	"digits": {
+		"0": 0, "1": 1, "2": 2, "3": 3, "4": 4,
+		"5": 5, "6": 6, "7": 7, "8": 8, "9": 9,
+	},
+	"english": {
+		"zero":  0,
+		"one":   1,
+		"two":   2,
+		"three": 3,
+		"four":  4,
+		"five":  5,
+		"six":   6,
+		"seven": 7,
+		"eight": 8,
+		"nine":  9,
+	},
+}
+
+// LoadVocabulary returns the builtin vocabulary called name, or otherwise
+// reads name as a file with one "word value" pair per line. Blank lines and
+// lines starting with '#' are ignored.
+func LoadVocabulary(name string) (Vocabulary, error) {
+	if words, ok := builtinVocabularies[name]; ok {
+		return Vocabulary{name, words}, nil
+	}
+
+	fh, err := os.Open(name)
+	if err != nil {
+		return Vocabulary{}, fmt.Errorf("%s is neither a builtin vocabulary nor a readable file: %w", name, err)
+	}
+	defer fh.Close()
+
+	vocab := Vocabulary{name, map[string]int{}}
+	scanner := bufio.NewScanner(fh)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return Vocabulary{}, fmt.Errorf("%s:%d: expected \"word value\", got %q", name, lineNo, line)
+		}
+		value, err := strconv.Atoi(fields[1])
+		if err != nil || value < 0 {
+			return Vocabulary{}, fmt.Errorf("%s:%d: invalid value %q", name, lineNo, fields[1])
+		}
+		if prev, ok := vocab.Words[fields[0]]; ok && prev != value {
+			return Vocabulary{}, fmt.Errorf("%s:%d: %q is already defined as %d", name, lineNo, fields[0], prev)
+		}
+		vocab.Words[fields[0]] = value
+	}
+	return vocab, scanner.Err()
+}
+
+// MergeVocabularies combines vocabularies into a single word -> value map. A
+// word defined by more than one vocabulary with different values is a
+// conflict, and all conflicts are reported together.
+func MergeVocabularies(vocabs []Vocabulary) (map[string]int, error) {
+	merged := map[string]int{}
+	source := map[string]string{}
+	conflicts := []string{}
+	for _, vocab := range vocabs {
+		for word, value := range vocab.Words {
+			prev, ok := merged[word]
+			if ok && prev != value {
+				conflicts = append(conflicts, fmt.Sprintf("%q is %d in %s but %d in %s", word, prev, source[word], value, vocab.Name))
+				continue
+			}
+			if !ok {
+				merged[word] = value
+				source[word] = vocab.Name
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		sort.Strings(conflicts)
+		return nil, fmt.Errorf("conflicting vocabulary entries:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+	return merged, nil
+}
+
+// loadVocabularies loads and merges a comma separated list of vocabularies.
+func loadVocabularies(names string) (map[string]int, error) {
+	vocabs := []Vocabulary{}
+	for _, name := range strings.Split(names, ",") {
+		vocab, err := LoadVocabulary(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		vocabs = append(vocabs, vocab)
+	}
+	return MergeVocabularies(vocabs)
+}
+
+// firstDigit and lastDigit pick the calibration digits out of a (possibly
+// multi-digit) vocabulary value.
+func firstDigit(value int) int {
+	for value >= 10 {
+		value /= 10
+	}
+	return value
+}
+
+func lastDigit(value int) int {
+	return value % 10
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// generate makes size games, each of one to six draws of up to 20 cubes of
+// each of the bag's colors. Counts run a little past the bag's limits so
+// that some games are impossible.
+func generate(rng *rand.Rand, size int) (string, error) {
+	colors := limits.colors()
+	var sb strings.Builder
+	for id := 1; id <= size; id++ {
+		draws := []string{}
+		for n := 1 + rng.Intn(6); n > 0; n-- {
+			shown := rng.Perm(len(colors))[:1+rng.Intn(len(colors))]
+			cubes := []string{}
+			for _, c := range shown {
+				cubes = append(cubes, fmt.Sprintf("%d %s", 1+rng.Intn(limits[colors[c]]+6), colors[c]))
+			}
+			draws = append(draws, strings.Join(cubes, ", "))
+		}
+		fmt.Fprintf(&sb, "Game %d: %s\n", id, strings.Join(draws, "; "))
+	}
+	return sb.String(), nil
+}
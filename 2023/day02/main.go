@@ -0,0 +1,304 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+// year and day of the puzzle
+const year, day = 2023, 2
+
+func check(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
+
+var example = flag.Bool("example", false, "Use example input instead of AoC URL")
+var workers = flag.Int("workers", 1, "Number of goroutines used to parse input")
+var bagFlag = flag.String("bag", "red=12,green=13,blue=14", "Cubes of each color in the bag, as comma separated color=count pairs")
+var bagFile = flag.String("bag-file", "", "Read the bag from a file of color=count lines instead of -bag")
+var lenient = flag.Bool("lenient", false, "Skip lines that fail to parse (reporting them on stderr) instead of failing")
+var report = flag.Bool("report", false, "Print the max per color and which games violate which limit")
+
+func init() {
+	utils.FileFlag("bag-file")
+}
+
+// draw maps each color to the number of cubes of that color in one handful
+type draw map[string]int
+
+// bag maps each color to the number of cubes of that color available
+type bag map[string]int
+
+type game struct {
+	id    int
+	hands []draw
+}
+
+// violation records a game that showed more cubes of a color than the bag holds
+type violation struct {
+	game  int
+	color string
+	count int
+	limit int
+}
+
+// limits is the bag the games are checked against, set from flags in main
+var limits = bag{"red": 12, "green": 13, "blue": 14}
+
+var LINE_REGEX = regexp.MustCompile(`^Game (?P<gameId>\d+): (.*)`)
+var BLOCK_REGEX = regexp.MustCompile(`^(\d+) (\w+)$`)
+
+// parseBag parses color=count pairs separated by commas or newlines. Blank
+// lines and lines starting with '#' are ignored.
+func parseBag(spec string) (bag, error) {
+	b := bag{}
+	for _, line := range strings.Split(spec, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, pair := range strings.Split(line, ",") {
+			color, countString, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid bag entry %q, expected color=count", pair)
+			}
+			count, err := strconv.Atoi(strings.TrimSpace(countString))
+			if err != nil {
+				return nil, fmt.Errorf("invalid count for %s: %w", color, err)
+			}
+			color = strings.TrimSpace(color)
+			if count < 0 {
+				return nil, fmt.Errorf("count for %s can't be negative, got %d", color, count)
+			}
+			if _, seen := b[color]; seen {
+				return nil, fmt.Errorf("color %s listed more than once", color)
+			}
+			b[color] = count
+		}
+	}
+	if len(b) == 0 {
+		return nil, fmt.Errorf("bag has no colors")
+	}
+	return b, nil
+}
+
+func loadBag() (bag, error) {
+	if *bagFile == "" {
+		return parseBag(*bagFlag)
+	}
+	contents, err := os.ReadFile(*bagFile)
+	if err != nil {
+		return nil, err
+	}
+	return parseBag(string(contents))
+}
+
+// colors returns the colors in the bag in a stable order
+func (b bag) colors() []string {
+	colors := make([]string, 0, len(b))
+	for color := range b {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+	return colors
+}
+
+func (b bag) String() string {
+	parts := []string{}
+	for _, color := range b.colors() {
+		parts = append(parts, fmt.Sprintf("%s=%d", color, b[color]))
+	}
+	return strings.Join(parts, ",")
+}
+
+// parseLine parses a game, rejecting any color that isn't in the bag or that
+// is listed twice in one draw. Errors carry the column of the fragment that
+// failed.
+func parseLine(line string, colors bag) (game, error) {
+	lineMatch := LINE_REGEX.FindStringSubmatchIndex(line)
+	if lineMatch == nil {
+		return game{}, utils.AtColumn(1, errors.New(`expected "Game <id>: <draws>"`))
+	}
+	gameId, err := strconv.Atoi(line[lineMatch[2]:lineMatch[3]])
+	if err != nil {
+		return game{}, utils.AtColumn(lineMatch[2]+1, err)
+	}
+
+	draws := []draw{}
+	// offset tracks the position in line of the fragment being parsed
+	offset := lineMatch[4]
+	for _, drawString := range strings.Split(line[lineMatch[4]:lineMatch[5]], "; ") {
+		d := draw{}
+		for _, blockString := range strings.Split(drawString, ", ") {
+			blockMatch := BLOCK_REGEX.FindStringSubmatchIndex(blockString)
+			if blockMatch == nil {
+				return game{}, utils.AtColumn(offset+1, fmt.Errorf(`expected "<count> <color>", got %q`, blockString))
+			}
+			num, err := strconv.Atoi(blockString[blockMatch[2]:blockMatch[3]])
+			if err != nil {
+				return game{}, utils.AtColumn(offset+blockMatch[2]+1, err)
+			}
+			color := blockString[blockMatch[4]:blockMatch[5]]
+			if _, ok := colors[color]; !ok {
+				return game{}, utils.AtColumn(offset+blockMatch[4]+1, fmt.Errorf("unknown color %q (bag has %s)", color, colors))
+			}
+			if _, ok := d[color]; ok {
+				return game{}, utils.AtColumn(offset+1, fmt.Errorf("color %q appears more than once in a draw", color))
+			}
+			d[color] = num
+			// blocks are separated by ", " and draws by "; ", which are the same length
+			offset += len(blockString) + len(", ")
+		}
+		draws = append(draws, d)
+	}
+	g := game{gameId, draws}
+	// fmt.Println(g, line)
+	return g, nil
+}
+
+// parseInput parses every game, or with -lenient every game that parses,
+// reporting the lines it skipped.
+func parseInput(input string) ([]game, error) {
+	parse := func(line string) (game, error) {
+		return parseLine(line, limits)
+	}
+	if !*lenient {
+		return utils.ParseAll(input, *workers, parse)
+	}
+
+	games, skipped := utils.ParseAllLenient(input, *workers, parse)
+	for _, err := range skipped {
+		utils.Log.Warn("skipping line", "error", err)
+	}
+	return games, nil
+}
+
+// violations lists every color for which draws exceed the bag's limit, with
+// the largest count seen for that color
+func (b bag) violations(g game) []violation {
+	result := []violation{}
+	seen := g.minimumBag()
+	for _, color := range seen.colors() {
+		if seen[color] > b[color] {
+			result = append(result, violation{g.id, color, seen[color], b[color]})
+		}
+	}
+	return result
+}
+
+func AllValid(draws []draw, limits bag) bool {
+	for _, hand := range draws {
+		for color, count := range hand {
+			if count > limits[color] {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// minimumBag is the smallest bag that could have produced every draw in the game
+func (g game) minimumBag() bag {
+	minBag := bag{}
+	for _, hand := range g.hands {
+		for color, count := range hand {
+			minBag[color] = max(minBag[color], count)
+		}
+	}
+	return minBag
+}
+
+// maxPerColor is the largest number of cubes of each color seen in any draw
+func maxPerColor(games []game) bag {
+	result := bag{}
+	for _, game := range games {
+		for color, count := range game.minimumBag() {
+			result[color] = max(result[color], count)
+		}
+	}
+	return result
+}
+
+func part1(games []game) int {
+	count := 0
+	for _, game := range games {
+		if AllValid(game.hands, limits) {
+			count += game.id
+		}
+	}
+	return count
+}
+
+func part2(games []game) int {
+	total := 0
+	for _, game := range games {
+		minBag := game.minimumBag()
+		// colors missing from the game count as 0, as they always have
+		product := 1
+		for _, color := range limits.colors() {
+			product *= minBag[color]
+		}
+		total += product
+	}
+	return total
+}
+
+func printReport(games []game) {
+	fmt.Println("Bag:", limits)
+	fmt.Println("Max per color:", maxPerColor(games))
+	for _, game := range games {
+		for _, v := range limits.violations(game) {
+			fmt.Printf("Game %d: %d %s exceeds limit of %d\n", v.game, v.count, v.color, v.limit)
+		}
+	}
+}
+
+var TEST_INPUT = `Game 1: 3 blue, 4 red; 1 red, 2 green, 6 blue; 2 green
+Game 2: 1 blue, 2 green; 3 green, 4 blue, 1 red; 1 green, 1 blue
+Game 3: 8 green, 6 blue, 20 red; 5 blue, 4 red, 13 green; 5 green, 1 red
+Game 4: 1 green, 3 red, 6 blue; 3 green, 6 red; 3 green, 15 blue, 14 red
+Game 5: 6 red, 1 blue, 3 green; 2 blue, 1 red, 2 green`
+
+func main() {
+	flag.Parse()
+
+	var err error
+	if limits, err = loadBag(); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid bag:", err)
+		os.Exit(2)
+	}
+
+	var input string
+	if *example {
+		input = TEST_INPUT
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 100)
+	} else {
+		input = utils.GetInputs(year, day)
+	}
+
+	// parse once, outside the parts, so -lenient reports skipped lines once
+	// and even when the answers come from the cache
+	games, err := parseInput(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "can't parse input:", err)
+		os.Exit(1)
+	}
+
+	utils.Solve(year, day, 1, input, func(string) int { return part1(games) })
+	utils.Solve(year, day, 2, input, func(string) int { return part2(games) })
+
+	if *report {
+		printReport(games)
+	}
+}
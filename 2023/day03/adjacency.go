@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// SymbolClass decides which characters in the schematic count as symbols.
+type SymbolClass func(char rune) bool
+
+// AnySymbol treats every character that isn't a digit or blank as a symbol.
+func AnySymbol(char rune) bool {
+	return !(unicode.IsDigit(char) || isBlank(char))
+}
+
+// ParseSymbolClass turns a flag value into a SymbolClass. "any" means
+// AnySymbol, anything else is the literal set of symbol characters.
+func ParseSymbolClass(spec string) (SymbolClass, error) {
+	if spec == "any" {
+		return AnySymbol, nil
+	}
+	if spec == "" {
+		return nil, fmt.Errorf("symbol class must be \"any\" or a set of characters")
+	}
+	for _, char := range spec {
+		if unicode.IsDigit(char) || isBlank(char) {
+			return nil, fmt.Errorf("%q can't be a symbol", char)
+		}
+	}
+	return func(char rune) bool {
+		return strings.ContainsRune(spec, char)
+	}, nil
+}
+
+// Adjacency indexes which numbers touch which symbols of one class, in both
+// directions.
+type Adjacency struct {
+	Schematic *Schematic
+	Symbols   map[Cell][]NumberInfo // every symbol -> the numbers touching it
+	Numbers   map[NumberInfo][]Cell // number -> the symbols it touches
+	numbers   []NumberInfo          // every number, in reading order
+}
+
+// Index builds the adjacency index between every number in the schematic
+// and every symbol of the given class, including symbols no number touches.
+func (s *Schematic) Index(class SymbolClass) *Adjacency {
+	a := &Adjacency{
+		Schematic: s,
+		Symbols:   map[Cell][]NumberInfo{},
+		Numbers:   map[NumberInfo][]Cell{},
+		numbers:   s.FindNumbers(),
+	}
+	for row, line := range s.Grid {
+		for col, char := range line {
+			if class(char) {
+				a.Symbols[Cell{row, col}] = []NumberInfo{}
+			}
+		}
+	}
+	for _, number := range a.numbers {
+		symbols := s.CheckNeighbors(number, class)
+		a.Numbers[number] = symbols
+		for _, symbol := range symbols {
+			a.Symbols[symbol] = append(a.Symbols[symbol], number)
+		}
+	}
+	return a
+}
+
+// Touching returns the symbols that touch exactly k numbers, in reading
+// order. A negative k matches any number of numbers, including none.
+func (a *Adjacency) Touching(k int) []Cell {
+	result := []Cell{}
+	for symbol, numbers := range a.Symbols {
+		if k < 0 || len(numbers) == k {
+			result = append(result, symbol)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Row != result[j].Row {
+			return result[i].Row < result[j].Row
+		}
+		return result[i].Col < result[j].Col
+	})
+	return result
+}
+
+// Isolated returns the numbers that touch no symbol, in reading order.
+func (a *Adjacency) Isolated() []NumberInfo {
+	result := []NumberInfo{}
+	for _, number := range a.numbers {
+		if len(a.Numbers[number]) == 0 {
+			result = append(result, number)
+		}
+	}
+	return result
+}
+
+// Aggregate applies f to the values of the numbers touching each of the given
+// symbols.
+func (a *Adjacency) Aggregate(symbols []Cell, f func(values []int) int) map[Cell]int {
+	result := map[Cell]int{}
+	for _, symbol := range symbols {
+		values := []int{}
+		for _, number := range a.Symbols[symbol] {
+			values = append(values, a.Schematic.GetNumberValue(number))
+		}
+		result[symbol] = f(values)
+	}
+	return result
+}
+
+// Some aggregation functions for use with Aggregate.
+
+func Product(values []int) int {
+	result := 1
+	for _, v := range values {
+		result *= v
+	}
+	return result
+}
+
+func Sum(values []int) (result int) {
+	for _, v := range values {
+		result += v
+	}
+	return result
+}
+
+func Max(values []int) (result int) {
+	for _, v := range values {
+		result = max(result, v)
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// generatedSymbols are the symbols that appear in real schematics
+const generatedSymbols = "*#+$/=%@&-"
+
+// generate makes a size by size schematic of '.', numbers of one to three
+// digits and symbols. Numbers are always separated from each other, and
+// about one cell in twelve is a symbol, so there are both part numbers and
+// numbers touching nothing, and gears with one, two or more neighbors.
+func generate(rng *rand.Rand, size int) (string, error) {
+	var sb strings.Builder
+	for row := 0; row < size; row++ {
+		line := make([]byte, 0, size)
+		for len(line) < size {
+			switch r := rng.Intn(12); {
+			case r == 0:
+				line = append(line, generatedSymbols[rng.Intn(len(generatedSymbols))])
+			case r <= 2:
+				digits := strconv.Itoa(1 + rng.Intn(999))
+				if len(line)+len(digits) > size {
+					line = append(line, '.')
+					continue
+				}
+				line = append(line, digits...)
+				// something must separate this number from the next
+				if len(line) < size {
+					line = append(line, '.')
+				}
+			default:
+				line = append(line, '.')
+			}
+		}
+		sb.Write(line)
+		sb.WriteByte('\n')
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+// year and day of the puzzle
+const year, day = 2023, 3
+
+// Cell represents a cell in the 2D array.
+type Cell struct {
+	Row, Col int
+}
+
+// NumberInfo represents information about a multi-digit number.
+type NumberInfo struct {
+	Start  Cell
+	Length int
+}
+
+// Schematic represents the 2D array of runes.
+type Schematic struct {
+	Grid       [][]rune
+	Rows, Cols int
+}
+
+// NewSchematic creates a new Schematic from a string, one row per line. Every
+// character is kept, including spaces. All rows must be the same width,
+// unless pad is set, in which case short rows are padded with '.' to the
+// width of the widest row.
+func NewSchematic(input string, pad bool) (*Schematic, error) {
+	var grid [][]rune
+	cols := 0
+
+	lines := utils.Lines(input)
+	for _, line := range lines {
+		lineRunes := []rune(strings.TrimSuffix(line.Text, "\r"))
+		grid = append(grid, lineRunes)
+		cols = max(cols, len(lineRunes))
+	}
+
+	for i, row := range grid {
+		if len(row) == cols {
+			continue
+		}
+		if !pad {
+			err := fmt.Errorf("row is %d wide but the widest row is %d (use -pad to pad short rows)", len(row), cols)
+			return nil, &utils.ParseError{Line: lines[i].Number, Text: lines[i].Text, Err: err}
+		}
+		for len(grid[i]) < cols {
+			grid[i] = append(grid[i], '.')
+		}
+	}
+
+	return &Schematic{Grid: grid, Rows: len(grid), Cols: cols}, nil
+}
+
+// isBlank reports whether a cell is empty space rather than a digit or symbol
+func isBlank(char rune) bool {
+	return char == '.' || unicode.IsSpace(char)
+}
+
+var example = flag.Bool("example", false, "Use example input instead of AoC URL")
+var pad = flag.Bool("pad", false, "Pad short rows with '.' instead of rejecting ragged input")
+var gear = flag.String("gear", "*", "Symbols that act as gears in part 2 (\"any\" or a set of characters)")
+var gearCount = flag.Int("gear-count", 2, "Number of adjacent numbers that makes a symbol a gear")
+var report = flag.Bool("report", false, "Print which numbers and symbols touch each other")
+
+// symbols is the class of characters that count as symbols, set by -symbols
+var symbols SymbolClass = AnySymbol
+
+func init() {
+	flag.Func("symbols", "Characters that count as symbols: \"any\" (default) or a set of characters", func(spec string) (err error) {
+		symbols, err = ParseSymbolClass(spec)
+		return err
+	})
+}
+
+func check(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
+
+// FindNumbers finds the locations and lengths of multi-digit numbers in the schematic.
+func (s *Schematic) GetNumberValue(numInfo NumberInfo) int {
+	currValue := 0
+	for i := 0; i < numInfo.Length; i++ {
+		char := s.Grid[numInfo.Start.Row][numInfo.Start.Col+i]
+		if !unicode.IsDigit(char) {
+			panic(fmt.Sprintf("bad conversion of %s", string(char)))
+		}
+		currValue = currValue*10 + int(char-'0')
+	}
+	return currValue
+}
+
+// FindNumbers finds the locations and lengths of multi-digit numbers in the schematic.
+func (s *Schematic) FindNumbers() []NumberInfo {
+	var numbers []NumberInfo
+	var currNumber *NumberInfo
+
+	for i, row := range s.Grid {
+		for j, char := range row {
+			if unicode.IsDigit(char) {
+				switch {
+				case currNumber == nil:
+					// Start of a number, find its length
+					start := Cell{Row: i, Col: j}
+					currNumber = &NumberInfo{start, 1}
+				case currNumber != nil:
+					currNumber.Length += 1
+				}
+			} else {
+				if currNumber != nil {
+					numbers = append(numbers, *currNumber)
+					currNumber = nil
+				}
+			}
+		}
+		if currNumber != nil {
+			numbers = append(numbers, *currNumber)
+			currNumber = nil
+		}
+	}
+
+	return numbers
+}
+
+// Neighbors returns the cells on the perimeter of a number, in reading order.
+// Each cell appears once, and cells outside the grid are left out.
+func (s *Schematic) Neighbors(number NumberInfo) []Cell {
+	// Coordinates of the number
+	row, col := number.Start.Row, number.Start.Col
+
+	// the rows above and below span one column past each end of the number,
+	// while on the number's own row only the two ends are neighbors
+	candidates := []Cell{}
+	for j := col - 1; j <= col+number.Length; j++ {
+		candidates = append(candidates, Cell{row - 1, j})
+	}
+	candidates = append(candidates, Cell{row, col - 1}, Cell{row, col + number.Length})
+	for j := col - 1; j <= col+number.Length; j++ {
+		candidates = append(candidates, Cell{row + 1, j})
+	}
+
+	result := []Cell{}
+	for _, c := range candidates {
+		if c.Row >= 0 && c.Row < len(s.Grid) && c.Col >= 0 && c.Col < len(s.Grid[c.Row]) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// CheckNeighbors returns the neighboring cells of a number that hold symbols
+// of the given class.
+func (s *Schematic) CheckNeighbors(number NumberInfo, class SymbolClass) []Cell {
+	result := []Cell{}
+	for _, neighbor := range s.Neighbors(number) {
+		if class(s.Grid[neighbor.Row][neighbor.Col]) {
+			result = append(result, neighbor)
+		}
+	}
+	return result
+}
+
+var TEST_INPUT = `467..114..
+...*......
+..35..633.
+......#...
+617*......
+.....+.58.
+..592.....
+......755.
+...$.*....
+.664.598..`
+
+func part1(input string) int {
+	schematic, err := NewSchematic(input, *pad)
+	check(err)
+
+	index := schematic.Index(symbols)
+
+	total := 0
+	for number, adjacentSymbols := range index.Numbers {
+		if len(adjacentSymbols) > 0 {
+			numValue := schematic.GetNumberValue(number)
+			total += numValue
+		}
+	}
+
+	return total
+}
+
+func part2(input string) int {
+	schematic, err := NewSchematic(input, *pad)
+	check(err)
+
+	gearClass, err := ParseSymbolClass(*gear)
+	check(err)
+	index := schematic.Index(gearClass)
+
+	total := 0
+	gears := index.Touching(*gearCount)
+	for _, ratio := range index.Aggregate(gears, Product) {
+		total += ratio
+	}
+
+	return total
+}
+
+// printReport summarizes how numbers and symbols touch each other
+func printReport(schematic *Schematic) {
+	fmt.Printf("schematic is %d rows by %d columns\n", schematic.Rows, schematic.Cols)
+
+	index := schematic.Index(symbols)
+
+	isolated := index.Isolated()
+	values := []int{}
+	for _, number := range isolated {
+		values = append(values, schematic.GetNumberValue(number))
+	}
+	fmt.Printf("%d numbers touch no symbol: %v\n", len(isolated), values)
+
+	// symbol -> number of adjacent numbers -> how many such symbols
+	counts := map[rune]map[int]int{}
+	for _, symbol := range index.Touching(-1) {
+		char := schematic.Grid[symbol.Row][symbol.Col]
+		if counts[char] == nil {
+			counts[char] = map[int]int{}
+		}
+		counts[char][len(index.Symbols[symbol])]++
+	}
+	chars := []rune{}
+	for char := range counts {
+		chars = append(chars, char)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+	for _, char := range chars {
+		ks := []int{}
+		for k := range counts[char] {
+			ks = append(ks, k)
+		}
+		sort.Ints(ks)
+		for _, k := range ks {
+			fmt.Printf("%d '%c' symbols touch %d numbers\n", counts[char][k], char, k)
+		}
+	}
+}
+
+func main() {
+	flag.Parse()
+
+	var input string
+	if *example {
+		input = TEST_INPUT
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 140)
+	} else {
+		input = utils.GetInputs(year, day)
+	}
+
+	schematic, err := NewSchematic(input, *pad)
+	check(err)
+
+	utils.Solve(year, day, 1, input, part1)
+	utils.Solve(year, day, 2, input, part2)
+
+	if *report {
+		printReport(schematic)
+	}
+}
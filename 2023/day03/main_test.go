@@ -0,0 +1,167 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNeighbors(t *testing.T) {
+	tests := []struct {
+		name   string
+		grid   string
+		number NumberInfo
+		want   []Cell
+	}{
+		{
+			name:   "top left corner",
+			grid:   "1..\n...\n...",
+			number: NumberInfo{Cell{0, 0}, 1},
+			want:   []Cell{{0, 1}, {1, 0}, {1, 1}},
+		},
+		{
+			name:   "top right corner",
+			grid:   ".12\n...\n...",
+			number: NumberInfo{Cell{0, 1}, 2},
+			want:   []Cell{{0, 0}, {1, 0}, {1, 1}, {1, 2}},
+		},
+		{
+			name:   "bottom left corner",
+			grid:   "...\n...\n12.",
+			number: NumberInfo{Cell{2, 0}, 2},
+			want:   []Cell{{1, 0}, {1, 1}, {1, 2}, {2, 2}},
+		},
+		{
+			name:   "bottom right corner",
+			grid:   "...\n...\n..1",
+			number: NumberInfo{Cell{2, 2}, 1},
+			want:   []Cell{{1, 1}, {1, 2}, {2, 1}},
+		},
+		{
+			name:   "top edge",
+			grid:   ".12..\n.....\n.....",
+			number: NumberInfo{Cell{0, 1}, 2},
+			want:   []Cell{{0, 0}, {0, 3}, {1, 0}, {1, 1}, {1, 2}, {1, 3}},
+		},
+		{
+			name:   "bottom edge",
+			grid:   ".....\n.....\n..3..",
+			number: NumberInfo{Cell{2, 2}, 1},
+			want:   []Cell{{1, 1}, {1, 2}, {1, 3}, {2, 1}, {2, 3}},
+		},
+		{
+			name:   "left edge",
+			grid:   "....\n12..\n....",
+			number: NumberInfo{Cell{1, 0}, 2},
+			want:   []Cell{{0, 0}, {0, 1}, {0, 2}, {1, 2}, {2, 0}, {2, 1}, {2, 2}},
+		},
+		{
+			name:   "right edge",
+			grid:   "....\n..45\n....",
+			number: NumberInfo{Cell{1, 2}, 2},
+			want:   []Cell{{0, 1}, {0, 2}, {0, 3}, {1, 1}, {2, 1}, {2, 2}, {2, 3}},
+		},
+		{
+			name:   "1x1 grid",
+			grid:   "7",
+			number: NumberInfo{Cell{0, 0}, 1},
+			want:   []Cell{},
+		},
+		{
+			name:   "number filling a row",
+			grid:   "...\n123\n...",
+			number: NumberInfo{Cell{1, 0}, 3},
+			want:   []Cell{{0, 0}, {0, 1}, {0, 2}, {2, 0}, {2, 1}, {2, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSchematic(tt.grid, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.Neighbors(tt.number); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Neighbors(%v) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckNeighbors(t *testing.T) {
+	tests := []struct {
+		name   string
+		grid   string
+		number NumberInfo
+		want   []Cell
+	}{
+		{
+			name:   "symbols at the corners of the perimeter",
+			grid:   "#...$\n.123.\n%...&",
+			number: NumberInfo{Cell{1, 1}, 3},
+			want:   []Cell{{0, 0}, {0, 4}, {2, 0}, {2, 4}},
+		},
+		{
+			name:   "symbol past the perimeter",
+			grid:   "......*\n.123...\n.......",
+			number: NumberInfo{Cell{1, 1}, 3},
+			want:   []Cell{},
+		},
+		{
+			name:   "number at a corner",
+			grid:   "12\n*.",
+			number: NumberInfo{Cell{0, 0}, 2},
+			want:   []Cell{{1, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := NewSchematic(tt.grid, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := s.CheckNeighbors(tt.number, AnySymbol); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CheckNeighbors(%v) = %v, want %v", tt.number, got, tt.want)
+			}
+		})
+	}
+}
+
+// A symbol diagonal to one digit and beside the next is on the perimeter
+// only once, so the number must only be indexed against it once.
+func TestIndexCountsSharedSymbolOnce(t *testing.T) {
+	for _, grid := range []string{"*..\n12.\n...", ".*.\n123\n...", "...\n12*\n..."} {
+		s, err := NewSchematic(grid, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		index := s.Index(AnySymbol)
+		symbols := index.Touching(-1)
+		if len(symbols) != 1 {
+			t.Fatalf("%q: found symbols %v, want exactly one", grid, symbols)
+		}
+		if numbers := index.Symbols[symbols[0]]; len(numbers) != 1 {
+			t.Errorf("%q: symbol at %v touches numbers %v, want the number once", grid, symbols[0], numbers)
+		}
+	}
+}
+
+func TestTouching(t *testing.T) {
+	s, err := NewSchematic("*....\n..12.\n....#", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := s.Index(AnySymbol)
+	tests := []struct {
+		k    int
+		want []Cell
+	}{
+		{0, []Cell{{0, 0}}},
+		{1, []Cell{{2, 4}}},
+		{2, []Cell{}},
+		{-1, []Cell{{0, 0}, {2, 4}}},
+	}
+	for _, tt := range tests {
+		if got := index.Touching(tt.k); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Touching(%d) = %v, want %v", tt.k, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// generate makes size cards of 10 winning numbers and 25 numbers you have,
+// all between 1 and 99 and distinct within each side. Like the real input,
+// no card has more matches than there are cards after it, and about half
+// the cards have matches in an input of the usual size.
+func generate(rng *rand.Rand, size int) (string, error) {
+	var sb strings.Builder
+	for number := 1; number <= size; number++ {
+		pool := rng.Perm(99)
+		for i := range pool {
+			pool[i]++
+		}
+		// every card with matches multiplies the copies of the cards after
+		// it, so they get rarer in bigger inputs to keep part 2's total from
+		// overflowing
+		matches := 0
+		if rng.Intn(size) < min(size/2, 100) {
+			matches = min(rng.Intn(11), size-number)
+		}
+
+		winning := pool[:10]
+		have := append(append([]int{}, winning[:matches]...), pool[10:10+25-matches]...)
+		rng.Shuffle(len(have), func(a, b int) { have[a], have[b] = have[b], have[a] })
+
+		fmt.Fprintf(&sb, "Card %*d: %s | %s\n", len(fmt.Sprint(size)), number, formatNumbers(winning), formatNumbers(have))
+	}
+	return sb.String(), nil
+}
+
+func formatNumbers(numbers []int) string {
+	fields := make([]string, len(numbers))
+	for i, n := range numbers {
+		fields[i] = fmt.Sprintf("%2d", n)
+	}
+	return strings.Join(fields, " ")
+}
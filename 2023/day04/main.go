@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+// year and day of the puzzle
+const year, day = 2023, 4
+
+func check(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
+
+var example = flag.Bool("example", false, "Use example input instead of AoC URL")
+var workers = flag.Int("workers", 1, "Number of goroutines used to parse input")
+
+var TEST_INPUT = `Card 1: 41 48 83 86 17 | 83 86  6 31 17  9 48 53
+Card 2: 13 32 20 16 61 | 61 30 68 82 17 32 24 19
+Card 3:  1 21 53 59 44 | 69 82 63 72 16 21 14  1
+Card 4: 41 92 73 84 69 | 59 84 76 51 58  5 54 83
+Card 5: 87 83 26 28 32 | 88 30 70 12 93 22 82 36
+Card 6: 31 18 13 56 72 | 74 77 10 23 35 67 36 11`
+
+// Card represents a parsed card
+type Card struct {
+	Number      int
+	Left, Right []int
+}
+
+// CardParser streams cards parsed from its input
+type CardParser struct {
+	Input   string
+	Workers int
+}
+
+// Parse parses the input in the background and sends cards to the returned
+// channel. Cancel ctx to stop parsing early.
+func (cp *CardParser) Parse(ctx context.Context) <-chan utils.Item[Card] {
+	stream := utils.Stream[Card]{Input: cp.Input, Parse: parseLine, Workers: cp.Workers}
+	return stream.Run(ctx)
+}
+
+func parseLine(line string) (Card, error) {
+	header, body, ok := strings.Cut(line, ":")
+	if !ok {
+		return Card{}, utils.AtColumn(1, errors.New("missing ':' after card number"))
+	}
+	numString, ok := strings.CutPrefix(header, "Card")
+	if !ok {
+		return Card{}, utils.AtColumn(1, errors.New("expected line to start with \"Card\""))
+	}
+	number, err := strconv.Atoi(strings.TrimSpace(numString))
+	if err != nil {
+		return Card{}, utils.AtColumn(len("Card")+1, err)
+	}
+
+	// columns are 1-based, and the body starts just after the ':'
+	bodyColumn := len(header) + 2
+	values, winners, ok := strings.Cut(body, "|")
+	if !ok {
+		return Card{}, utils.AtColumn(bodyColumn, errors.New("missing '|' separator"))
+	}
+	left, err := stringToInts(values, bodyColumn)
+	if err != nil {
+		return Card{}, err
+	}
+	right, err := stringToInts(winners, bodyColumn+len(values)+1)
+	if err != nil {
+		return Card{}, err
+	}
+
+	return Card{
+		Number: number,
+		Left:   left,
+		Right:  right,
+	}, nil
+}
+
+// stringToInts parses whitespace separated ints, where column is the position
+// of values within the line (for error reporting).
+func stringToInts(values string, column int) ([]int, error) {
+	var ints []int
+	offset := 0
+	for _, s := range strings.Fields(values) {
+		i := offset + strings.Index(values[offset:], s)
+		offset = i + len(s)
+		v, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, utils.AtColumn(column+i, err)
+		}
+		ints = append(ints, v)
+	}
+	return ints, nil
+}
+
+func intersection(a, b []int) []int {
+	var result []int
+
+	// Create a map to track elements in b
+	bMap := make(map[int]bool)
+	for _, v := range b {
+		bMap[v] = true
+	}
+
+	// Iterate a, add common elements
+	for _, v := range a {
+		if bMap[v] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func part1(ctx context.Context, input string) (result int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parser := &CardParser{Input: input, Workers: *workers}
+	for item := range parser.Parse(ctx) {
+		check(item.Err)
+		card := item.Value
+		common := intersection(card.Left, card.Right)
+		if len(common) > 0 {
+			score := 1 << (len(common) - 1)
+			result += score
+		}
+	}
+
+	return result
+}
+
+// checkNumbering verifies that cards arrive numbered 1, 2, 3, ... with no
+// gaps, duplicates or reordering, since copies are won by position.
+func checkNumbering(prev, number int) error {
+	switch {
+	case number == prev:
+		return fmt.Errorf("card %d appears more than once", number)
+	case number < prev:
+		return fmt.Errorf("card %d is out of order (follows card %d)", number, prev)
+	case number == prev+2:
+		return fmt.Errorf("card %d is missing", prev+1)
+	case number > prev+1:
+		return fmt.Errorf("cards %d to %d are missing", prev+1, number-1)
+	}
+	return nil
+}
+
+func part2(ctx context.Context, input string) (result int) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	parser := &CardParser{Input: input, Workers: *workers}
+
+	// won[i] is the number of copies won so far of the card i places after the
+	// current one. It only ever needs to reach as far as the largest number of
+	// matches on a single card, so memory use doesn't grow with the input.
+	won := []int{}
+	prev := 0
+	for item := range parser.Parse(ctx) {
+		check(item.Err)
+		card := item.Value
+		if err := checkNumbering(prev, card.Number); err != nil {
+			check(&utils.ParseError{Line: item.Line.Number, Text: item.Line.Text, Err: err})
+		}
+		prev = card.Number
+
+		count := 1
+		if len(won) > 0 {
+			count += won[0]
+			won = won[1:]
+		}
+		result += count
+
+		matches := len(intersection(card.Left, card.Right))
+		for len(won) < matches {
+			won = append(won, 0)
+		}
+		for j := 0; j < matches; j++ {
+			won[j] += count
+		}
+	}
+
+	// stopping early leaves copies won of cards that were never read
+	if ctx.Err() != nil {
+		return result
+	}
+	// The puzzle promises that cards never win copies of cards past the end
+	// of the table, so treat that as malformed input rather than guessing.
+	if len(won) > 0 {
+		panic(fmt.Sprintf("card %d wins copies of %d card(s) past the end of the table", prev, len(won)))
+	}
+
+	return result
+}
+
+func main() {
+	flag.Parse()
+
+	var input string
+	if *example {
+		input = TEST_INPUT
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 200)
+	} else {
+		input = utils.GetInputs(year, day)
+	}
+
+	utils.SolveContext(year, day, 1, input, part1)
+	utils.SolveContext(year, day, 2, input, part2)
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+var generatedMaps = []string{"seed-to-soil", "soil-to-fertilizer", "fertilizer-to-water", "water-to-light", "light-to-temperature", "temperature-to-humidity", "humidity-to-location"}
+
+// generatedSpan is the range of numbers the generated maps rearrange, about
+// as large as in the real input
+const generatedSpan = 1 << 32
+
+// generate makes an almanac of 10 seed ranges and seven maps of size entries
+// each. Each map cuts part of [0, generatedSpan) into size blocks and lays
+// them out again in a shuffled order, so like the real maps its source ranges
+// never overlap and neither do its destination ranges.
+func generate(rng *rand.Rand, size int) (string, error) {
+	var sb strings.Builder
+
+	seeds := []string{}
+	for i := 0; i < 10; i++ {
+		start := rng.Int63n(generatedSpan)
+		length := 1 + rng.Int63n(min(generatedSpan-start, generatedSpan/20))
+		seeds = append(seeds, fmt.Sprint(start), fmt.Sprint(length))
+	}
+	fmt.Fprintf(&sb, "seeds: %s\n", strings.Join(seeds, " "))
+
+	for _, name := range generatedMaps {
+		fmt.Fprintf(&sb, "\n%s map:\n", name)
+
+		// size+1 distinct cut points make size blocks
+		cuts := map[int64]bool{}
+		for len(cuts) < size+1 {
+			cuts[rng.Int63n(generatedSpan)] = true
+		}
+		points := []int64{}
+		for cut := range cuts {
+			points = append(points, cut)
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i] < points[j] })
+
+		order := rng.Perm(size)
+		dest := make([]int64, size)
+		next := points[0]
+		for _, block := range order {
+			dest[block] = next
+			next += points[block+1] - points[block]
+		}
+		for _, block := range rng.Perm(size) {
+			fmt.Fprintf(&sb, "%d %d %d\n", dest[block], points[block], points[block+1]-points[block])
+		}
+	}
+	return sb.String(), nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"math"
@@ -11,6 +12,9 @@ import (
 	"github.com/skirklin/aoc2023/utils"
 )
 
+// year and day of the puzzle
+const year, day = 2023, 5
+
 func check(e error) {
 	if e != nil {
 		panic(e)
@@ -142,7 +146,7 @@ func (p1 LinearPiecewise) compose(p2 LinearPiecewise) LinearPiecewise {
 		span += seg.source.End - seg.source.Start
 	}
 	if span != math.MaxInt64 {
-		fmt.Println(ranges)
+		utils.Log.Error("composed ranges don't cover int64", "ranges", ranges)
 		panic(fmt.Sprintf("function doesn't cover all of int64. %d != %d (diff = %d)", span, math.MaxInt64, math.MaxInt64-span))
 	}
 	return LinearPiecewise{ranges}
@@ -173,7 +177,7 @@ func parseBlock(input string) (result LinearPiecewise) {
 	for i := range ranges[:len(ranges)-1] {
 		r := Range{ranges[i].source.End, ranges[i+1].source.Start}
 		if r.Start > r.End {
-			fmt.Println(r)
+			utils.Log.Error("ranges overlap", "gap", r)
 			panic("invalid overlap")
 		}
 		if r.End-r.Start > 0 {
@@ -213,7 +217,7 @@ func part1(input string) (result int64) {
 	return result
 }
 
-func part2(input string) (result int64) {
+func part2(ctx context.Context, input string) (result int64) {
 	chunks := strings.Split(input, "\n\n")
 	// make ranges instead of a single array
 	seeds := stringsToInts(strings.Fields(strings.Split(chunks[0], ":")[1]))
@@ -226,6 +230,10 @@ func part2(input string) (result int64) {
 	mapping := LinearPiecewise{[]Segment{unit}}
 
 	for _, chunk := range chunks[1:] {
+		// composing can multiply the number of segments, so this is the slow part
+		if ctx.Err() != nil {
+			return result
+		}
 		pwfunc := parseBlock(chunk)
 		mapping = mapping.compose(pwfunc)
 	}
@@ -260,13 +268,12 @@ func main() {
 	var input string
 	if *example {
 		input = TEST_INPUT
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 30)
 	} else {
-		input = utils.GetInputs(2023, 5)
+		input = utils.GetInputs(year, day)
 	}
 
-	ans1 := part1(input)
-	fmt.Println("Part 1 answer:", ans1)
-
-	ans2 := part2(input)
-	fmt.Println("Part 2 answer:", ans2)
+	utils.Solve(year, day, 1, input, part1)
+	utils.SolveContext(year, day, 2, input, part2)
 }
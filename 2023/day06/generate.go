@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// maxRaces is the most races a generated input can have. Part 2 runs every
+// time together into one number, which for more races would be longer than
+// the real input's 8 digits (and soon wouldn't fit in an int at all).
+const maxRaces = 8
+
+// generate makes size races with records a fair way below the best possible
+// distance. Times have two digits like the real input for up to four races,
+// and one digit beyond that so part 2's race stays 8 digits at most. The
+// races are regenerated until that long race can also be won, as it always
+// can in the real input.
+func generate(rng *rand.Rand, size int) (string, error) {
+	if size > maxRaces {
+		return "", fmt.Errorf("day 6 inputs can have at most %d races, or part 2's race gets too long to solve", maxRaces)
+	}
+	shortest, longest := 10, 99
+	if size > 4 {
+		shortest, longest = 2, 9
+	}
+
+	for {
+		times, distances := []string{}, []string{}
+		for i := 0; i < size; i++ {
+			t := shortest + rng.Intn(longest-shortest+1)
+			best := (t / 2) * (t - t/2)
+			d := best/2 + rng.Intn(best-best/2)
+			times = append(times, fmt.Sprint(t))
+			distances = append(distances, fmt.Sprint(d))
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Time:    ")
+		for i := range times {
+			fmt.Fprintf(&sb, " %*s", max(len(times[i]), len(distances[i]))+3, times[i])
+		}
+		fmt.Fprintf(&sb, "\nDistance:")
+		for i := range distances {
+			fmt.Fprintf(&sb, " %*s", max(len(times[i]), len(distances[i]))+3, distances[i])
+		}
+		sb.WriteByte('\n')
+
+		input := sb.String()
+		long := parseInputs2(input)
+		if (long.time/2)*(long.time-long.time/2) > long.distance {
+			return input, nil
+		}
+	}
+}
@@ -2,7 +2,6 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"math"
 	"strconv"
 	"strings"
@@ -10,6 +9,9 @@ import (
 	"github.com/skirklin/aoc2023/utils"
 )
 
+// year and day of the puzzle
+const year, day = 2023, 6
+
 func check(e error) {
 	if e != nil {
 		panic(e)
@@ -131,13 +133,12 @@ func main() {
 	var input string
 	if *example {
 		input = TEST_INPUT
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 4)
 	} else {
-		input = utils.GetInputs(2023, 6)
+		input = utils.GetInputs(year, day)
 	}
 
-	ans1 := part1(input)
-	fmt.Println("Part 1 answer:", ans1)
-
-	ans2 := part2(input)
-	fmt.Println("Part 2 answer:", ans2)
+	utils.Solve(year, day, 1, input, part1)
+	utils.Solve(year, day, 2, input, part2)
 }
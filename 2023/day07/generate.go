@@ -0,0 +1,35 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// generate makes size distinct hands with bids from 1 to 1000. Cards are
+// drawn from a few favorites per hand so that pairs, full houses and so on
+// turn up about as often as in the real input, rather than almost every hand
+// being high card or one pair.
+func generate(rng *rand.Rand, size int) (string, error) {
+	cards := "23456789TJQKA"
+	// there are only so many distinct hands
+	size = min(size, 13*13*13*13*13)
+	seen := map[string]bool{}
+	var sb strings.Builder
+	for len(seen) < size {
+		favorites := make([]byte, 1+rng.Intn(5))
+		for i := range favorites {
+			favorites[i] = cards[rng.Intn(len(cards))]
+		}
+		hand := make([]byte, 5)
+		for i := range hand {
+			hand[i] = favorites[rng.Intn(len(favorites))]
+		}
+		if seen[string(hand)] {
+			continue
+		}
+		seen[string(hand)] = true
+		fmt.Fprintf(&sb, "%s %d\n", hand, 1+rng.Intn(1000))
+	}
+	return sb.String(), nil
+}
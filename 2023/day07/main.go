@@ -10,6 +10,9 @@ import (
 	"github.com/skirklin/aoc2023/utils"
 )
 
+// year and day of the puzzle
+const year, day = 2023, 7
+
 func check(e error) {
 	if e != nil {
 		panic(e)
@@ -17,6 +20,7 @@ func check(e error) {
 }
 
 var example = flag.Bool("example", false, "Use example input instead of AoC URL")
+var workers = flag.Int("workers", 1, "Number of goroutines used to parse input")
 
 var TEST_INPUT = `32T3K 765
 T55J5 684
@@ -167,11 +171,10 @@ func parseHand(line string, ruleset RuleSet) Hand {
 }
 
 func parseHands(input string, ruleset RuleSet) []Hand {
-	lines := strings.Split(strings.Trim(input, "\n"), "\n")
-	hands := make([]Hand, len(lines))
-	for i, line := range lines {
-		hands[i] = parseHand(line, ruleset)
-	}
+	hands, err := utils.ParseAll(input, *workers, func(line string) (Hand, error) {
+		return parseHand(line, ruleset), nil
+	})
+	check(err)
 
 	// to get this effect, I'll convert each hand to a 6 rune string containing the scores, then sort those.
 	sort.Slice(hands, func(i, j int) bool {
@@ -196,9 +199,8 @@ func part1(input string) (result int) {
 	hands := parseHands(input, ruleset)
 
 	result = 0
-	fmt.Println("sorted order:")
 	for i, hand := range hands {
-		fmt.Println(" ", hand)
+		utils.Log.Debug("sorted", "rank", i+1, "hand", hand)
 		result += (i + 1) * hand.bid
 	}
 	return result
@@ -209,9 +211,8 @@ func part2(input string) (result int) {
 	hands := parseHands(input, ruleset)
 
 	result = 0
-	fmt.Println("sorted order:")
 	for i, hand := range hands {
-		fmt.Println(" ", hand)
+		utils.Log.Debug("sorted", "rank", i+1, "hand", hand)
 		result += (i + 1) * hand.bid
 	}
 	return result
@@ -223,13 +224,12 @@ func main() {
 	var input string
 	if *example {
 		input = TEST_INPUT
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 1000)
 	} else {
-		input = utils.GetInputs(2023, 7)
+		input = utils.GetInputs(year, day)
 	}
 
-	ans1 := part1(input)
-	fmt.Println("Part 1 answer:", ans1)
-
-	ans2 := part2(input)
-	fmt.Println("Part 2 answer:", ans2)
+	utils.Solve(year, day, 1, input, part1)
+	utils.Solve(year, day, 2, input, part2)
 }
@@ -0,0 +1,319 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+var cacheCommands = []command{
+	{"ls", "list cached inputs", cacheListCommand},
+	{"rm", "remove cached inputs so they're fetched again", cacheRemoveCommand},
+	{"verify", "check cached inputs aren't empty or error pages", cacheVerifyCommand},
+	{"refetch", "download cached inputs again, replacing the cached copies", cacheRefetchCommand},
+	{"export", "write every cached input to an archive", cacheExportCommand},
+	{"import", "add the inputs from an archive to the cache", cacheImportCommand},
+}
+
+// cachedInputs lists the cached inputs for a year, or for every year if year
+// is 0
+func cachedInputs(year int) ([]utils.CachedInput, error) {
+	inputs, err := utils.CachedInputs()
+	if err != nil || year == 0 {
+		return inputs, err
+	}
+	selected := []utils.CachedInput{}
+	for _, input := range inputs {
+		if input.Year == year {
+			selected = append(selected, input)
+		}
+	}
+	return selected, nil
+}
+
+func cacheListCommand(args []string) error {
+	fs := flag.NewFlagSet("cache ls", flag.ExitOnError)
+	year := fs.Int("year", 0, "Only list inputs for this event year (default all years)")
+	format := fs.String("format", "table", "Output format: table or markdown")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc cache ls [-year Y] [-format F]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	inputs, err := cachedInputs(*year)
+	if err != nil {
+		return err
+	}
+	t := table{header: []string{"Year", "Day", "Size", "Fetched", "SHA-256"}}
+	for _, input := range inputs {
+		hash, err := input.Hash()
+		if err != nil {
+			return err
+		}
+		t.add(strconv.Itoa(input.Year), strconv.Itoa(input.Day), strconv.FormatInt(input.Size, 10), input.Fetched.Local().Format(time.DateTime), hash[:16])
+	}
+	return t.render(os.Stdout, *format)
+}
+
+func cacheRemoveCommand(args []string) error {
+	fs := flag.NewFlagSet("cache rm", flag.ExitOnError)
+	year := yearFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc cache rm [-year Y] days...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	// no default here, removing every input should be deliberate
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no days given")
+	}
+	days, err := parseDays(*year, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if !utils.InputCached(*year, day) {
+			fmt.Printf("%d day %d: not cached\n", *year, day)
+			continue
+		}
+		if err := utils.RemoveInput(*year, day); err != nil {
+			return err
+		}
+		fmt.Printf("%d day %d: removed\n", *year, day)
+	}
+	return nil
+}
+
+// badInputs returns the cached inputs for a year (or all years) that fail
+// utils.CheckInput, with the reason each one failed.
+func badInputs(year int) ([]utils.CachedInput, []error, error) {
+	inputs, err := cachedInputs(year)
+	if err != nil {
+		return nil, nil, err
+	}
+	bad, problems := []utils.CachedInput{}, []error{}
+	for _, input := range inputs {
+		data, err := os.ReadFile(input.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := utils.CheckInput(data); err != nil {
+			bad = append(bad, input)
+			problems = append(problems, err)
+		}
+	}
+	return bad, problems, nil
+}
+
+func cacheVerifyCommand(args []string) error {
+	fs := flag.NewFlagSet("cache verify", flag.ExitOnError)
+	year := fs.Int("year", 0, "Only check inputs for this event year (default all years)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc cache verify [-year Y]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	inputs, err := cachedInputs(*year)
+	if err != nil {
+		return err
+	}
+	bad, problems, err := badInputs(*year)
+	if err != nil {
+		return err
+	}
+	for i, input := range bad {
+		fmt.Printf("%d day %d: %v\n", input.Year, input.Day, problems[i])
+	}
+	if len(bad) > 0 {
+		return fmt.Errorf("%d of %d cached input(s) look wrong, replace them with aoc cache refetch", len(bad), len(inputs))
+	}
+	fmt.Printf("%d cached input(s) look fine\n", len(inputs))
+	return nil
+}
+
+func cacheRefetchCommand(args []string) error {
+	fs := flag.NewFlagSet("cache refetch", flag.ExitOnError)
+	year := yearFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc cache refetch [-year Y] [days...]")
+		fmt.Fprintln(fs.Output(), "Without days, refetches the year's cached inputs that fail aoc cache verify.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var days []int
+	if fs.NArg() > 0 {
+		var err error
+		if days, err = parseDays(*year, fs.Args()); err != nil {
+			return err
+		}
+	} else {
+		if err := checkYear(*year); err != nil {
+			return err
+		}
+		bad, _, err := badInputs(*year)
+		if err != nil {
+			return err
+		}
+		for _, input := range bad {
+			days = append(days, input.Day)
+		}
+		if len(days) == 0 {
+			fmt.Printf("no cached inputs for %d need refetching\n", *year)
+			return nil
+		}
+	}
+	if !utils.Offline() {
+		if err := validateSession(); err != nil {
+			return err
+		}
+	}
+
+	for _, day := range days {
+		changed, err := utils.RefetchInputs(*year, day)
+		if err != nil {
+			return err
+		}
+		if changed {
+			fmt.Printf("%d day %d: updated\n", *year, day)
+		} else {
+			fmt.Printf("%d day %d: unchanged\n", *year, day)
+		}
+	}
+	return nil
+}
+
+func cacheCommand(args []string) error {
+	usage := func() {
+		fmt.Fprintf(os.Stderr, "usage: aoc cache <command> [args]\n\ncommands:\n")
+		for _, c := range cacheCommands {
+			fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.summary)
+		}
+	}
+	if len(args) == 0 {
+		usage()
+		return fmt.Errorf("no cache command given")
+	}
+	for _, c := range cacheCommands {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
+	}
+	usage()
+	return fmt.Errorf("unknown cache command %q", args[0])
+}
+
+// passphraseFlag adds -passphrase-file, for encrypting and decrypting cache
+// archives
+func passphraseFlag(fs *flag.FlagSet) *string {
+	return fs.String("passphrase-file", "", "Read the archive passphrase from this file (default $AOC_CACHE_PASSPHRASE, if set)")
+}
+
+// readPassphrase returns the passphrase from -passphrase-file or the
+// environment, or "" if there isn't one.
+func readPassphrase(path string) (string, error) {
+	if path == "" {
+		return os.Getenv("AOC_CACHE_PASSPHRASE"), nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	passphrase := strings.TrimRight(string(data), "\r\n")
+	if passphrase == "" {
+		return "", fmt.Errorf("%s is empty", path)
+	}
+	return passphrase, nil
+}
+
+func cacheExportCommand(args []string) error {
+	fs := flag.NewFlagSet("cache export", flag.ExitOnError)
+	output := fs.String("o", "-", "Write the archive to this file, - for stdout")
+	passphraseFile := passphraseFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc cache export [-o file] [-passphrase-file file]")
+		fmt.Fprintln(fs.Output(), "The archive is encrypted if a passphrase is given.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	passphrase, err := readPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if *output != "-" {
+		fh, err := os.OpenFile(*output, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		out = fh
+	}
+	count, err := utils.ExportCache(out, passphrase)
+	if err != nil {
+		return err
+	}
+
+	encrypted := "unencrypted, keep it private"
+	if passphrase != "" {
+		encrypted = "encrypted"
+	}
+	fmt.Fprintf(os.Stderr, "exported %d input(s) (%s)\n", count, encrypted)
+	return nil
+}
+
+func cacheImportCommand(args []string) error {
+	fs := flag.NewFlagSet("cache import", flag.ExitOnError)
+	overwrite := fs.Bool("overwrite", false, "Replace cached inputs that differ from the archive's")
+	passphraseFile := passphraseFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc cache import [-overwrite] [-passphrase-file file] archive")
+		fmt.Fprintln(fs.Output(), "Use - as the archive to read it from stdin.")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected one archive")
+	}
+
+	passphrase, err := readPassphrase(*passphraseFile)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if fs.Arg(0) != "-" {
+		fh, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		in = fh
+	}
+	summary, err := utils.ImportCache(in, passphrase, *overwrite)
+	if errors.Is(err, utils.ErrNeedPassphrase) {
+		return fmt.Errorf("%w (use -passphrase-file or $AOC_CACHE_PASSPHRASE)", err)
+	} else if err != nil {
+		return err
+	}
+
+	fmt.Printf("imported %d input(s), %d already cached\n", summary.Imported, summary.Unchanged)
+	if len(summary.Conflicts) > 0 {
+		return fmt.Errorf("kept the cached copies of %s, which differ from the archive (use -overwrite to replace them)", strings.Join(summary.Conflicts, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+// yearFlag adds the -year flag shared by the commands, defaulting to the
+// most recent event.
+func yearFlag(fs *flag.FlagSet) *int {
+	return fs.Int("year", utils.LatestEvent(), "Event year")
+}
+
+// checkYear rejects years there was no event for
+func checkYear(year int) error {
+	if year < utils.FirstEvent || year > utils.LatestEvent() {
+		return fmt.Errorf("invalid year %d, events run from %d to %d", year, utils.FirstEvent, utils.LatestEvent())
+	}
+	return nil
+}
+
+// dayDir is the directory holding a day's solution, e.g. 2023/day05
+func dayDir(year, day int) string {
+	return filepath.Join(strconv.Itoa(year), fmt.Sprintf("day%02d", day))
+}
+
+// localDays lists the days of a year that have a solution directory, in
+// order
+func localDays(year int) ([]int, error) {
+	dirs, err := filepath.Glob(filepath.Join(strconv.Itoa(year), "day*"))
+	if err != nil {
+		return nil, err
+	}
+	days := []int{}
+	for _, dir := range dirs {
+		day, err := strconv.Atoi(strings.TrimPrefix(filepath.Base(dir), "day"))
+		if err != nil {
+			continue
+		}
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			days = append(days, day)
+		}
+	}
+	sort.Ints(days)
+	return days, nil
+}
+
+// parseDays turns command line arguments into day numbers, defaulting to
+// every local day of the year
+func parseDays(year int, args []string) ([]int, error) {
+	if err := checkYear(year); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return localDays(year)
+	}
+	days := []int{}
+	for _, arg := range args {
+		day, err := strconv.Atoi(arg)
+		if err != nil || day < 1 || day > 25 {
+			return nil, fmt.Errorf("invalid day %q", arg)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
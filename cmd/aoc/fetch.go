@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+func fetchCommand(args []string) error {
+	fs := flag.NewFlagSet("fetch", flag.ExitOnError)
+	year := yearFlag(fs)
+	wait := fs.Bool("wait", false, "Count down to the puzzle unlocking and fetch it as soon as it's available")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc fetch [-year Y] [-wait] days...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no days given")
+	}
+
+	days, err := parseDays(*year, fs.Args())
+	if err != nil {
+		return err
+	}
+	if err := checkSession(*year, days); err != nil {
+		return err
+	}
+	for _, day := range days {
+		input, err := utils.LoadInputs(*year, day, *wait)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d day %d: %d bytes\n", *year, day, len(input))
+	}
+	return nil
+}
+
+// checkSession makes sure the session is logged in before fetching any
+// inputs that aren't cached yet, so an expired session fails with a clear
+// message up front rather than part way through.
+func checkSession(year int, days []int) error {
+	if utils.Offline() {
+		// LoadInputs explains which inputs are missing
+		return nil
+	}
+	for _, day := range days {
+		if !utils.InputCached(year, day) {
+			return validateSession()
+		}
+	}
+	return nil
+}
+
+// validateSession makes sure the session is logged in to adventofcode.com
+func validateSession() error {
+	session, err := utils.ResolveSession()
+	if err != nil {
+		return err
+	}
+	if _, err := session.WhoAmI(); err != nil {
+		return fmt.Errorf("checking session from %s: %w", session.Source, err)
+	}
+	return nil
+}
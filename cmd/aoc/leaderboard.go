@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+func leaderboardCommand(args []string) error {
+	fs := flag.NewFlagSet("leaderboard", flag.ExitOnError)
+	year := yearFlag(fs)
+	id := fs.Int("id", envInt("AOC_LEADERBOARD_ID"), "Private leaderboard id (default $AOC_LEADERBOARD_ID)")
+	format := fs.String("format", "table", "Output format: table or markdown")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc leaderboard [-year Y] [-id N] [-format F] [days...]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := checkYear(*year); err != nil {
+		return err
+	}
+	if *id == 0 {
+		return fmt.Errorf("no leaderboard id, pass -id or set AOC_LEADERBOARD_ID")
+	}
+
+	session, err := utils.ResolveSession()
+	if err != nil {
+		return err
+	}
+	board, err := session.Leaderboard(*year, *id)
+	if err != nil {
+		return err
+	}
+
+	days := []int{}
+	if fs.NArg() > 0 {
+		days, err = parseDays(*year, fs.Args())
+		if err != nil {
+			return err
+		}
+	} else {
+		// every day anyone has a star on
+		for day := 1; day <= 25; day++ {
+			for _, m := range board.Members {
+				if _, ok := m.StarTime(day, 1); ok {
+					days = append(days, day)
+					break
+				}
+			}
+		}
+	}
+
+	out := os.Stdout
+	fmt.Fprintf(out, "Leaderboard %d for %d, as of %s\n", *id, *year, board.Fetched.Local().Format(time.DateTime))
+
+	members := board.Standings()
+	heading(out, *format, "Standings")
+	standings := table{header: []string{"Rank", "Name", "Score", "Stars"}}
+	for i, m := range members {
+		standings.add(strconv.Itoa(i+1), m.DisplayName(), strconv.Itoa(m.LocalScore), strconv.Itoa(m.Stars))
+	}
+	if err := standings.render(out, *format); err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		unlock := utils.UnlockTime(*year, day)
+		heading(out, *format, fmt.Sprintf("Day %d", day))
+		t := table{header: []string{"Name", "Part 1", "Part 2", "Part 1 to 2"}}
+		for _, m := range members {
+			first, ok1 := m.StarTime(day, 1)
+			second, ok2 := m.StarTime(day, 2)
+			if !ok1 {
+				continue
+			}
+			row := []string{m.DisplayName(), sinceUnlock(first, unlock), "", ""}
+			if ok2 {
+				row[2] = sinceUnlock(second, unlock)
+				row[3] = formatDuration(second.Sub(first))
+			}
+			t.add(row...)
+		}
+		if err := t.render(out, *format); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sinceUnlock shows when a star was earned as time since the puzzle
+// unlocked, like the site's personal stats page
+func sinceUnlock(t, unlock time.Time) string {
+	return formatDuration(t.Sub(unlock))
+}
+
+// formatDuration renders a duration as [Nd ]hh:mm:ss
+func formatDuration(d time.Duration) string {
+	if d < 0 {
+		return "-" + formatDuration(-d)
+	}
+	d = d.Round(time.Second)
+	days := d / (24 * time.Hour)
+	d -= days * 24 * time.Hour
+	s := fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	if days > 0 {
+		s = fmt.Sprintf("%dd %s", days, s)
+	}
+	return s
+}
+
+func envInt(name string) int {
+	n, _ := strconv.Atoi(os.Getenv(name))
+	return n
+}
@@ -0,0 +1,56 @@
+// Command aoc runs and manages this repo's Advent of Code solutions. It is
+// meant to be run from the root of the repo.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"run", "run days' solutions, carrying on past failures", runCommand},
+	{"fetch", "download and cache days' inputs", fetchCommand},
+	{"puzzle", "download puzzle descriptions into <year>/dayNN/README.md", puzzleCommand},
+	{"status", "show stars per day alongside the local solutions", statusCommand},
+	{"leaderboard", "show a private leaderboard's standings and star times", leaderboardCommand},
+	{"whoami", "check which account the session is logged in as", whoamiCommand},
+	{"cache", "list, check, refetch and move cached inputs", cacheCommand},
+}
+
+func usage() {
+	fmt.Fprintf(flag.CommandLine.Output(), "usage: aoc [flags] <command> [args]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(flag.CommandLine.Output(), "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(flag.CommandLine.Output(), "\nflags:\n")
+	flag.PrintDefaults()
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	for _, c := range commands {
+		if c.name == flag.Arg(0) {
+			if err := c.run(flag.Args()[1:]); err != nil {
+				fmt.Fprintln(os.Stderr, "aoc:", err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+	fmt.Fprintf(os.Stderr, "aoc: unknown command %q\n", flag.Arg(0))
+	usage()
+	os.Exit(2)
+}
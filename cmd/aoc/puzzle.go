@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+// readmeMarker records how many parts a README was written with, so it can
+// be refreshed once part two becomes available.
+var readmeMarker = regexp.MustCompile(`<!-- aoc puzzle .* parts: (\d) -->`)
+
+func puzzleCommand(args []string) error {
+	fs := flag.NewFlagSet("puzzle", flag.ExitOnError)
+	year := yearFlag(fs)
+	force := fs.Bool("force", false, "Download the description even if the README already has both parts")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc puzzle [-year Y] [-force] days...")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no days given")
+	}
+	days, err := parseDays(*year, fs.Args())
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		if err := writeReadme(*year, day, *force); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeReadme stores a day's puzzle description as README.md in the day's
+// directory. An existing README is only replaced when the site has more of
+// the puzzle than it does (i.e. part two has unlocked), or with force.
+func writeReadme(year, day int, force bool) error {
+	path := filepath.Join(dayDir(year, day), "README.md")
+	have := 0
+	if existing, err := os.ReadFile(path); err == nil {
+		if match := readmeMarker.FindSubmatch(existing); match != nil {
+			have, _ = strconv.Atoi(string(match[1]))
+		}
+	}
+	if have >= 2 && !force {
+		fmt.Printf("%s already has both parts\n", path)
+		return nil
+	}
+
+	session, err := utils.ResolveSession()
+	if err != nil {
+		return err
+	}
+	puzzle, err := session.Puzzle(year, day)
+	if err != nil {
+		return err
+	}
+	if len(puzzle.Parts) <= have && !force {
+		fmt.Printf("%s is up to date (part %d isn't available yet)\n", path, have+1)
+		return nil
+	}
+
+	if err := os.MkdirAll(dayDir(year, day), 0750); err != nil {
+		return err
+	}
+	contents := fmt.Sprintf("<!-- aoc puzzle https://adventofcode.com/%d/day/%d parts: %d -->\n\n%s", year, day, len(puzzle.Parts), puzzle.Markdown())
+	if err := os.WriteFile(path, []byte(contents), 0640); err != nil {
+		return err
+	}
+	fmt.Printf("wrote %s (%d part(s))\n", path, len(puzzle.Parts))
+	return nil
+}
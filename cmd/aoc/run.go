@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// runCommand runs each requested day as its own process, so a day that
+// crashes outright can't take the rest of the batch down with it. The flags
+// set on aoc itself (like -offline or -force) are passed on to every day, as
+// are any arguments after "--", so they must be flags every day defines
+// (like -example or -timeout). Flags for a single day are given as day:flag,
+// e.g. 2:-bag=red=1,green=1,blue=1 or 5:-timeout=5m, and take precedence
+// over the shared ones.
+func runCommand(args []string) error {
+	fs := flag.NewFlagSet("run", flag.ExitOnError)
+	year := yearFlag(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc run [-year Y] [day | day:flag ...] [-- flags for every day...]")
+		fs.PrintDefaults()
+	}
+	// split before parsing, as the flag package swallows a leading "--"
+	passthrough := []string{}
+	if i := slices.Index(args, "--"); i != -1 {
+		args, passthrough = args[:i], args[i+1:]
+	}
+	fs.Parse(args)
+
+	dayArgs, perDay, err := splitDayFlags(fs.Args())
+	if err != nil {
+		return err
+	}
+	days, err := parseDays(*year, dayArgs)
+	if err != nil {
+		return err
+	}
+	if len(days) == 0 {
+		return fmt.Errorf("no solutions for %d (expected %d/dayNN directories)", *year, *year)
+	}
+
+	shared := append(globalFlags(), passthrough...)
+	failures := []string{}
+	for _, day := range days {
+		failures = append(failures, runDay(*year, day, append(slices.Clone(shared), perDay[day]...))...)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%d failure(s):\n  %s", len(failures), strings.Join(failures, "\n  "))
+	}
+	return nil
+}
+
+// globalFlags returns the flags set on aoc's command line. They all come
+// from the utils package, which every day is built on, so each day accepts
+// them too.
+func globalFlags() []string {
+	args := []string{}
+	flag.Visit(func(f *flag.Flag) {
+		args = append(args, fmt.Sprintf("-%s=%s", f.Name, f.Value))
+	})
+	return args
+}
+
+// splitDayFlags separates day:flag arguments from plain days, returning the
+// days mentioned (in order, without repeats) and each day's flags.
+func splitDayFlags(args []string) ([]string, map[int][]string, error) {
+	days := []string{}
+	perDay := map[int][]string{}
+	for _, arg := range args {
+		dayString, dayFlag, found := strings.Cut(arg, ":")
+		if !slices.Contains(days, dayString) {
+			days = append(days, dayString)
+		}
+		if !found {
+			continue
+		}
+		day, err := strconv.Atoi(dayString)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid day %q in %q", dayString, arg)
+		}
+		if !strings.HasPrefix(dayFlag, "-") {
+			return nil, nil, fmt.Errorf("expected a flag after %q in %q", dayString+":", arg)
+		}
+		perDay[day] = append(perDay[day], dayFlag)
+	}
+	return days, perDay, nil
+}
+
+// runDay runs a single day, prefixing its answers with the year and day, and
+// returns a description of anything that went wrong.
+func runDay(year, day int, args []string) []string {
+	name := fmt.Sprintf("%d day %d", year, day)
+	cmd := exec.Command("go", append([]string{"run", "./" + dayDir(year, day)}, args...)...)
+	cmd.Stderr = os.Stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", name, err)}
+	}
+	if err := cmd.Start(); err != nil {
+		return []string{fmt.Sprintf("%s: %v", name, err)}
+	}
+
+	failures := []string{}
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		line := scanner.Text()
+		fmt.Printf("%s: %s\n", name, line)
+		if strings.HasPrefix(line, "Part ") && strings.Contains(line, " failed: ") {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, line))
+		}
+	}
+
+	// parts that fail are reported above; a non-zero exit means the day
+	// died outside of its parts (e.g. bad flags or no input)
+	if err := cmd.Wait(); err != nil {
+		failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+	}
+	return failures
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+func statusCommand(args []string) error {
+	fs := flag.NewFlagSet("status", flag.ExitOnError)
+	year := yearFlag(fs)
+	refresh := fs.Bool("refresh", false, "Fetch the calendar even if a recent copy is cached")
+	format := fs.String("format", "table", "Output format: table or markdown")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc status [-year Y] [-refresh] [-format F]")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if err := checkYear(*year); err != nil {
+		return err
+	}
+
+	session, err := utils.ResolveSession()
+	if err != nil {
+		return err
+	}
+	calendar, err := session.Calendar(*year, *refresh)
+	if err != nil {
+		return err
+	}
+
+	t := table{header: []string{"Day", "Stars", "Solution", "Tests"}}
+	total := 0
+	for _, day := range calendar.Days() {
+		if utils.UnlockTime(*year, day).After(time.Now()) {
+			break
+		}
+		stars := calendar[day]
+		total += stars
+		dir := dayDir(*year, day)
+		solution, tests := "", ""
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			solution = dir
+			if found, _ := filepath.Glob(filepath.Join(dir, "*_test.go")); len(found) > 0 {
+				tests = "yes"
+			} else {
+				tests = "no"
+			}
+		}
+		t.add(strconv.Itoa(day), strings.Repeat("*", stars), solution, tests)
+	}
+
+	fmt.Printf("%d: %d stars\n", *year, total)
+	return t.render(os.Stdout, *format)
+}
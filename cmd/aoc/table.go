@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// table is a simple grid of strings that can be rendered for the terminal or
+// as Markdown.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+func (t *table) render(w io.Writer, format string) error {
+	switch format {
+	case "table":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(t.header, "\t"))
+		for _, row := range t.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	case "markdown":
+		escape := strings.NewReplacer("|", `\|`)
+		line := func(cells []string) {
+			escaped := make([]string, len(cells))
+			for i, cell := range cells {
+				escaped[i] = escape.Replace(cell)
+			}
+			fmt.Fprintf(w, "| %s |\n", strings.Join(escaped, " | "))
+		}
+		line(t.header)
+		separators := make([]string, len(t.header))
+		for i := range separators {
+			separators[i] = "---"
+		}
+		line(separators)
+		for _, row := range t.rows {
+			line(row)
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown format %q, expected table or markdown", format)
+}
+
+// heading writes a section title in the given format
+func heading(w io.Writer, format, title string) {
+	if format == "markdown" {
+		fmt.Fprintf(w, "\n## %s\n\n", title)
+	} else {
+		fmt.Fprintf(w, "\n%s\n%s\n", title, strings.Repeat("=", len(title)))
+	}
+}
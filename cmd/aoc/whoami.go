@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/skirklin/aoc2023/utils"
+)
+
+func whoamiCommand(args []string) error {
+	fs := flag.NewFlagSet("whoami", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: aoc [-profile name] whoami")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	session, err := utils.ResolveSession()
+	if err != nil {
+		return err
+	}
+	name, err := session.WhoAmI()
+	if err != nil {
+		return fmt.Errorf("checking session from %s: %w", session.Source, err)
+	}
+
+	fmt.Printf("logged in as %s (session from %s)\n", name, session.Source)
+	if session.Expires.IsZero() {
+		fmt.Println("session expiry unknown, add \"expires\" to the profile to be warned before it runs out")
+	} else {
+		days := int(time.Until(session.Expires).Hours() / 24)
+		fmt.Printf("session expires %s (in %d days)\n", session.Expires.Local().Format(time.DateOnly), days)
+	}
+	return nil
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"flag"
-	"fmt"
+	"math/rand"
 
 	"github.com/skirklin/aoc2023/utils"
 )
@@ -13,12 +13,22 @@ func check(e error) {
 	}
 }
 
+// year and day of the puzzle, set these when copying the template to
+// <year>/dayNN
+const year, day = 0, 0
+
 var example = flag.Bool("example", false, "Use example input instead of AoC URL")
 
 var TEST_INPUT = `
 ...
 `
 
+// generate makes a random input with the same structure as the real one,
+// size lines long
+func generate(rng *rand.Rand, size int) (string, error) {
+	return "", nil
+}
+
 func part1(input string) (result int) {
 	return result
 }
@@ -33,13 +43,12 @@ func main() {
 	var input string
 	if *example {
 		input = TEST_INPUT
+	} else if utils.Generating() {
+		input = utils.Generate(generate, 1000)
 	} else {
-		input = utils.GetInputs(2023, -1)
+		input = utils.GetInputs(year, day)
 	}
 
-	ans1 := part1(input)
-	fmt.Println("Part 1 answer:", ans1)
-
-	ans2 := part2(input)
-	fmt.Println("Part 2 answer:", ans2)
+	utils.Solve(year, day, 1, input, part1)
+	utils.Solve(year, day, 2, input, part2)
 }
@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// An exported cache is a gzipped tar of <year>/<day> input files. Inputs
+// aren't meant to be shared publicly, so it can be encrypted with a
+// passphrase: the archive is then sealed with AES-256-GCM under a key derived
+// from the passphrase with PBKDF2-SHA256, and prefixed with encryptedMagic,
+// a random salt and the nonce.
+const (
+	encryptedMagic   = "aoc-cache-encrypted-v1\n"
+	saltSize         = 16
+	pbkdf2Iterations = 600_000
+	maxArchivedInput = 16 << 20 // far larger than any real input
+)
+
+// ErrNeedPassphrase is returned when importing an encrypted archive without
+// a passphrase.
+var ErrNeedPassphrase = errors.New("cache archive is encrypted, a passphrase is needed")
+
+// archiveName matches the names of inputs within an archive
+var archiveName = regexp.MustCompile(`^(\d{4})/(\d{1,2})$`)
+
+// ExportCache writes every cached input to w as an archive, encrypted if a
+// passphrase is given. It returns how many inputs were written.
+func ExportCache(w io.Writer, passphrase string) (int, error) {
+	inputs, err := CachedInputs()
+	if err != nil {
+		return 0, err
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, input := range inputs {
+		data, err := os.ReadFile(input.Path)
+		if err != nil {
+			return 0, err
+		}
+		header := &tar.Header{
+			Name:    fmt.Sprintf("%d/%d", input.Year, input.Day),
+			Mode:    0640,
+			Size:    int64(len(data)),
+			ModTime: input.Fetched,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return 0, err
+		}
+		if _, err := tw.Write(data); err != nil {
+			return 0, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+
+	archive := buf.Bytes()
+	if passphrase != "" {
+		if archive, err = encryptArchive(archive, passphrase); err != nil {
+			return 0, err
+		}
+	}
+	_, err = w.Write(archive)
+	return len(inputs), err
+}
+
+// ImportSummary describes what ImportCache did with each input in an archive.
+type ImportSummary struct {
+	Imported  int
+	Unchanged int      // already cached with the same contents
+	Conflicts []string // cached with different contents, and left alone
+}
+
+// ImportCache adds the inputs in an archive written by ExportCache to the
+// local cache. Inputs that are already cached with different contents are
+// only replaced if overwrite is set.
+func ImportCache(r io.Reader, passphrase string, overwrite bool) (*ImportSummary, error) {
+	archive, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(archive, []byte(encryptedMagic)) {
+		if passphrase == "" {
+			return nil, ErrNeedPassphrase
+		}
+		if archive, err = decryptArchive(archive, passphrase); err != nil {
+			return nil, err
+		}
+	} else if passphrase != "" {
+		Log.Warn("cache archive isn't encrypted, ignoring the passphrase")
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("reading cache archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	summary := &ImportSummary{}
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return summary, fmt.Errorf("reading cache archive: %w", err)
+		}
+
+		// never trust names from an archive enough to use them as paths
+		match := archiveName.FindStringSubmatch(header.Name)
+		if match == nil || header.Typeflag != tar.TypeReg {
+			Log.Warn("skipping unexpected entry in cache archive", "name", header.Name)
+			continue
+		}
+		if header.Size > maxArchivedInput {
+			return summary, fmt.Errorf("%s in cache archive is too large (%d bytes) to be an input", header.Name, header.Size)
+		}
+		year, _ := strconv.Atoi(match[1])
+		day, _ := strconv.Atoi(match[2])
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return summary, fmt.Errorf("reading %s from cache archive: %w", header.Name, err)
+		}
+
+		path := inputPath(year, day)
+		if existing, err := os.ReadFile(path); err == nil {
+			if bytes.Equal(existing, data) {
+				summary.Unchanged++
+				continue
+			}
+			if !overwrite {
+				summary.Conflicts = append(summary.Conflicts, header.Name)
+				continue
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return summary, err
+		}
+		Log.Debug("importing input", "path", path)
+		if err := os.WriteFile(path, data, 0640); err != nil {
+			return summary, err
+		}
+		if err := os.Chtimes(path, header.ModTime, header.ModTime); err != nil {
+			return summary, err
+		}
+		summary.Imported++
+	}
+	return summary, nil
+}
+
+func encryptArchive(archive []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := archiveCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	header := append(append([]byte(encryptedMagic), salt...), nonce...)
+	return gcm.Seal(header, nonce, archive, header), nil
+}
+
+func decryptArchive(archive []byte, passphrase string) ([]byte, error) {
+	rest := archive[len(encryptedMagic):]
+	if len(rest) < saltSize {
+		return nil, errors.New("cache archive is truncated")
+	}
+	salt := rest[:saltSize]
+	gcm, err := archiveCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	headerSize := len(encryptedMagic) + saltSize + gcm.NonceSize()
+	if len(archive) < headerSize {
+		return nil, errors.New("cache archive is truncated")
+	}
+	header, sealed := archive[:headerSize], archive[headerSize:]
+	plain, err := gcm.Open(nil, header[len(encryptedMagic)+saltSize:], sealed, header)
+	if err != nil {
+		return nil, errors.New("can't decrypt cache archive: wrong passphrase or corrupted archive")
+	}
+	return plain, nil
+}
+
+func archiveCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(pbkdf2Key([]byte(passphrase), salt, pbkdf2Iterations, 32))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2Key derives a key from a password as in RFC 8018, using
+// HMAC-SHA256 as the pseudorandom function.
+func pbkdf2Key(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	key := []byte{}
+	u := make([]byte, 0, prf.Size())
+	for block := uint32(1); len(key) < keyLen; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, block))
+		u = prf.Sum(u[:0])
+		t := append([]byte{}, u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
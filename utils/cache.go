@@ -0,0 +1,148 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachedInput is a puzzle input in the local cache.
+type CachedInput struct {
+	Year, Day int
+	Path      string
+	Size      int64
+	Fetched   time.Time // when it was written to the cache
+}
+
+// inputPath is where the input for a given year and day is cached
+func inputPath(year, day int) string {
+	return fmt.Sprintf("%s/%d/%d", cacheRoot, year, day)
+}
+
+// CachedInputs lists the inputs in the local cache, ordered by year and day.
+// Only <year>/<day> files count; the dot directories holding results,
+// calendars and so on are not inputs.
+func CachedInputs() ([]CachedInput, error) {
+	years, err := os.ReadDir(cacheRoot)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	inputs := []CachedInput{}
+	for _, y := range years {
+		year, err := strconv.Atoi(y.Name())
+		if err != nil || !y.IsDir() {
+			continue
+		}
+		days, err := os.ReadDir(filepath.Join(cacheRoot, y.Name()))
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range days {
+			day, err := strconv.Atoi(d.Name())
+			if err != nil || !d.Type().IsRegular() {
+				continue
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil, err
+			}
+			inputs = append(inputs, CachedInput{year, day, inputPath(year, day), info.Size(), info.ModTime()})
+		}
+	}
+	sort.Slice(inputs, func(i, j int) bool {
+		if inputs[i].Year != inputs[j].Year {
+			return inputs[i].Year < inputs[j].Year
+		}
+		return inputs[i].Day < inputs[j].Day
+	})
+	return inputs, nil
+}
+
+// Hash is the SHA-256 of the input, in hex.
+func (input CachedInput) Hash() (string, error) {
+	data, err := os.ReadFile(input.Path)
+	if err != nil {
+		return "", err
+	}
+	return Fingerprint(string(data)), nil
+}
+
+// errorPages are fragments of the pages adventofcode.com serves in place of
+// an input, which must never be mistaken for one.
+var errorPages = []string{
+	"<!DOCTYPE",
+	"<html",
+	"Puzzle inputs differ by user",
+	"Please log in to get your puzzle input",
+	"Please don't repeatedly request this endpoint before it unlocks",
+}
+
+// CheckInput looks for signs that data isn't really a puzzle input: that it
+// is empty, or is an error or login page saved in its place.
+func CheckInput(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return errors.New("input is empty")
+	}
+	start := string(data[:min(len(data), 512)])
+	for _, page := range errorPages {
+		if strings.Contains(start, page) {
+			return fmt.Errorf("input is an error page (contains %q)", page)
+		}
+	}
+	return nil
+}
+
+// storeInput writes an input to the cache, after checking it looks like one.
+func storeInput(year, day int, data []byte) error {
+	if err := CheckInput(data); err != nil {
+		return fmt.Errorf("not caching input for %d day %d: %w", year, day, err)
+	}
+	path := inputPath(year, day)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+	Log.Debug("caching input", "path", path)
+	return os.WriteFile(path, data, 0640)
+}
+
+// RemoveInput deletes an input from the cache, so the next run fetches it
+// again. Removing an input that isn't cached is not an error.
+func RemoveInput(year, day int) error {
+	err := os.Remove(inputPath(year, day))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// RefetchInputs downloads an input again, replacing the cached copy only once
+// the new one has been fetched and checked. It reports whether the contents
+// changed.
+func RefetchInputs(year, day int) (changed bool, err error) {
+	if *offline {
+		return false, fmt.Errorf("refetching input for %d day %d: %w", year, day, ErrOffline)
+	}
+	session, err := ResolveSession()
+	if err != nil {
+		return false, err
+	}
+	Log.Info("refetching input from web", "year", year, "day", day)
+	data, err := session.FetchInputs(year, day)
+	if err != nil {
+		return false, err
+	}
+	old, _ := os.ReadFile(inputPath(year, day))
+	if err := storeInput(year, day, data); err != nil {
+		return false, err
+	}
+	return !bytes.Equal(old, data), nil
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// CalendarRefreshInterval is how long a cached calendar page is reused
+// before fetching it again.
+const CalendarRefreshInterval = 15 * time.Minute
+
+// Calendar maps each day shown on an event's calendar to the number of stars
+// earned on it (0, 1 or 2).
+type Calendar map[int]int
+
+// Days returns the days on the calendar in order. Not every event has 25.
+func (c Calendar) Days() []int {
+	days := make([]int, 0, len(c))
+	for day := range c {
+		days = append(days, day)
+	}
+	sort.Ints(days)
+	return days
+}
+
+var calendarDayRegex = regexp.MustCompile(`class="calendar-day(\d+)( calendar-complete| calendar-verycomplete)?"`)
+
+// ParseCalendar reads the stars for each day out of an event's calendar page.
+func ParseCalendar(page []byte) (Calendar, error) {
+	// the calendar is shown to everyone, but stars only when logged in
+	if !bytes.Contains(page, []byte(`<div class="user">`)) {
+		return nil, errors.New("calendar page is not logged in (is the session valid?)")
+	}
+
+	calendar := Calendar{}
+	for _, match := range calendarDayRegex.FindAllSubmatch(page, -1) {
+		day, err := strconv.Atoi(string(match[1]))
+		if err != nil {
+			return nil, err
+		}
+		switch string(match[2]) {
+		case " calendar-verycomplete":
+			calendar[day] = 2
+		case " calendar-complete":
+			calendar[day] = 1
+		default:
+			calendar[day] = 0
+		}
+	}
+	if len(calendar) == 0 {
+		return nil, errors.New("no days found on calendar page")
+	}
+	return calendar, nil
+}
+
+// Calendar returns the stars earned on each day of an event, from a cached
+// copy of the calendar page if it is fresher than CalendarRefreshInterval
+// (unless refresh is set). Offline, any cached copy is used.
+func (session Session) Calendar(year int, refresh bool) (Calendar, error) {
+	path := filepath.Join(cacheRoot, ".calendar", strconv.Itoa(year)+".html")
+	if info, err := os.Stat(path); err == nil && (*offline || !refresh && time.Since(info.ModTime()) < CalendarRefreshInterval) {
+		Log.Debug("reading calendar from cache", "path", path)
+		page, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return ParseCalendar(page)
+	}
+
+	url := fmt.Sprintf("https://adventofcode.com/%d", year)
+	Log.Info("fetching calendar", "url", url)
+	resp, err := DefaultClient().Get(session, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, &StatusError{url, resp.Status}
+	}
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// only cache pages that parse, so a logged out page isn't reused
+	calendar, err := ParseCalendar(page)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, page, 0640); err != nil {
+		return nil, err
+	}
+	return calendar, nil
+}
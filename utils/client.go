@@ -0,0 +1,165 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+var contact = flag.String("contact", os.Getenv("AOC_CONTACT"), "Contact info (e.g. an email address) included in the User-Agent sent to adventofcode.com (default $AOC_CONTACT)")
+var requestTimeout = flag.Duration("request-timeout", 30*time.Second, "Give up on a request to adventofcode.com after this long")
+var offline = flag.Bool("offline", os.Getenv("AOC_OFFLINE") != "", "Never contact adventofcode.com, only use what is already cached (default true if $AOC_OFFLINE is set)")
+
+// ErrOffline is returned instead of making a request when -offline is set.
+var ErrOffline = errors.New("not contacting adventofcode.com in offline mode")
+
+// Offline reports whether -offline is set.
+func Offline() bool {
+	return *offline
+}
+
+// Client is an HTTP client for adventofcode.com that plays nicely with the
+// site: it identifies itself, keeps a minimum interval between requests (even
+// across separate processes), and retries transient failures with
+// exponential backoff.
+type Client struct {
+	HTTP        *http.Client
+	UserAgent   string
+	MinInterval time.Duration // minimum time between the start of two requests
+	Retries     int           // how many times to retry network errors and 5xx responses
+	Backoff     time.Duration // delay before the first retry, doubling after each
+	StateFile   string        // records the time of the last request, shared between processes
+}
+
+var (
+	defaultClient     *Client
+	defaultClientOnce sync.Once
+)
+
+// DefaultClient is the Client shared by everything in utils, configured from
+// flags. Flags must be parsed before it is first used.
+func DefaultClient() *Client {
+	defaultClientOnce.Do(func() {
+		userAgent := "github.com/skirklin/aoc2023"
+		if *contact != "" {
+			userAgent += " by " + *contact
+		}
+		defaultClient = &Client{
+			HTTP:        &http.Client{Timeout: *requestTimeout},
+			UserAgent:   userAgent,
+			MinInterval: 5 * time.Second,
+			Retries:     3,
+			Backoff:     2 * time.Second,
+			StateFile:   filepath.Join(cacheRoot, ".last-request"),
+		}
+	})
+	return defaultClient
+}
+
+// Do sends req, waiting for the rate limit first and retrying network errors
+// and 5xx responses. Only requests without a body are supported, since they
+// may be sent more than once.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		return nil, errors.New("aoc client only supports requests without a body")
+	}
+	if *offline {
+		return nil, fmt.Errorf("GET %s: %w", req.URL, ErrOffline)
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	delay := c.Backoff
+	for attempt := 0; ; attempt++ {
+		if err := c.throttle(req.Context()); err != nil {
+			return nil, err
+		}
+		Log.Debug("requesting", "url", req.URL.String(), "attempt", attempt+1)
+		resp, err := c.HTTP.Do(req)
+
+		retryable := err != nil || resp.StatusCode >= 500
+		if err != nil && req.Context().Err() != nil {
+			retryable = false
+		}
+		if !retryable || attempt >= c.Retries {
+			return resp, err
+		}
+
+		if err != nil {
+			Log.Warn("request failed, retrying", "url", req.URL.String(), "error", err, "delay", delay)
+		} else {
+			Log.Warn("server error, retrying", "url", req.URL.String(), "status", resp.Status, "delay", delay)
+			resp.Body.Close()
+		}
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+		delay *= 2
+	}
+}
+
+// Get is a convenience wrapper around Do for a GET carrying the session's
+// cookies.
+func (c *Client) Get(session Session, url string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.AddCookie(&http.Cookie{Name: "session", Value: session.SessionID})
+	req.AddCookie(&http.Cookie{Name: "_gid", Value: session.GID})
+	return c.Do(req)
+}
+
+// throttle waits until at least MinInterval has passed since the last
+// request made by any process sharing StateFile, then records the current
+// time as the last request.
+func (c *Client) throttle(ctx context.Context) error {
+	if c.MinInterval <= 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(c.StateFile), 0750); err != nil {
+		return err
+	}
+	fh, err := os.OpenFile(c.StateFile, os.O_RDWR|os.O_CREATE, 0640)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	// hold the lock while waiting so that concurrent processes queue up
+	// rather than all firing as soon as the interval is up
+	if err := lockFile(fh); err != nil {
+		return err
+	}
+	defer unlockFile(fh)
+
+	buf := make([]byte, 64)
+	n, _ := fh.ReadAt(buf, 0)
+	if last, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(buf[:n]))); err == nil {
+		if wait := time.Until(last.Add(c.MinInterval)); wait > 0 {
+			Log.Debug("rate limiting", "wait", wait)
+			select {
+			case <-time.After(wait):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	if err := fh.Truncate(0); err != nil {
+		return err
+	}
+	_, err = fh.WriteAt([]byte(time.Now().Format(time.RFC3339Nano)), 0)
+	if err != nil {
+		return fmt.Errorf("recording request time: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+var profile = flag.String("profile", os.Getenv("AOC_PROFILE"), "Credentials profile to use from the credentials file (default $AOC_PROFILE)")
+
+// ErrSessionInvalid is returned when adventofcode.com doesn't accept the
+// session, usually because it has expired.
+var ErrSessionInvalid = errors.New("session is not logged in to adventofcode.com (expired or invalid?)")
+
+// sessionExpiryWarning is how far ahead of a known expiry date to start
+// warning about it.
+const sessionExpiryWarning = 7 * 24 * time.Hour
+
+// Credentials is the contents of the credentials file: named profiles, so
+// that one person can switch between several accounts.
+type Credentials struct {
+	Profiles map[string]Profile `json:"profiles"`
+}
+
+// Profile is one account's session cookies. Expires is optional, copied from
+// the cookie's expiry in the browser, and is only used to warn before the
+// session runs out.
+type Profile struct {
+	SessionID string    `json:"session"`
+	GID       string    `json:"gid,omitempty"`
+	Expires   time.Time `json:"expires,omitempty"`
+}
+
+// CredentialsPath is where the credentials file lives: $AOC_CREDENTIALS if
+// set, otherwise aoc/credentials.json in the user's config directory.
+func CredentialsPath() (string, error) {
+	if path := os.Getenv("AOC_CREDENTIALS"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aoc", "credentials.json"), nil
+}
+
+// LoadCredentials reads the credentials file, refusing to use it if anyone
+// but its owner can read or write it.
+func LoadCredentials(path string) (*Credentials, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	info, err := fh.Stat()
+	if err != nil {
+		return nil, err
+	}
+	// windows doesn't have meaningful permission bits
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("%s is accessible by other users (mode %04o), run chmod 600 on it", path, info.Mode().Perm())
+	}
+
+	var creds Credentials
+	data, err := io.ReadAll(fh)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &creds, nil
+}
+
+// ResolveSession works out which session to use:
+//
+//   - the profile named by -profile (or $AOC_PROFILE) from the credentials file
+//   - otherwise $AOC_SESSION_ID and $AOC_GID, if set
+//   - otherwise the "default" profile from the credentials file
+//
+// It fails rather than returning an empty session, and refuses sessions
+// known to have expired.
+func ResolveSession() (Session, error) {
+	if *profile == "" && os.Getenv("AOC_SESSION_ID") != "" {
+		return Session{SessionID: os.Getenv("AOC_SESSION_ID"), GID: os.Getenv("AOC_GID"), Source: "environment"}, nil
+	}
+
+	name := *profile
+	if name == "" {
+		name = "default"
+	}
+	path, err := CredentialsPath()
+	if err != nil {
+		return Session{}, err
+	}
+	creds, err := LoadCredentials(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Session{}, fmt.Errorf("no session configured: set AOC_SESSION_ID or add a %q profile to %s", name, path)
+	} else if err != nil {
+		return Session{}, err
+	}
+	p, ok := creds.Profiles[name]
+	if !ok || p.SessionID == "" {
+		names := []string{}
+		for n := range creds.Profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return Session{}, fmt.Errorf("no session for profile %q in %s (have %s)", name, path, strings.Join(names, ", "))
+	}
+
+	session := Session{SessionID: p.SessionID, GID: p.GID, Expires: p.Expires, Source: fmt.Sprintf("profile %q in %s", name, path)}
+	if !p.Expires.IsZero() {
+		if remaining := time.Until(p.Expires); remaining <= 0 {
+			return Session{}, fmt.Errorf("session for %s expired on %s, log in again and update it", session.Source, p.Expires.Local().Format(time.DateOnly))
+		} else if remaining < sessionExpiryWarning {
+			Log.Warn("session expires soon", "source", session.Source, "expires", p.Expires.Local().Format(time.DateTime))
+		}
+	}
+	return session, nil
+}
+
+var userRegex = regexp.MustCompile(`<div class="user">([^<]*)`)
+
+// WhoAmI checks the session against adventofcode.com, returning the name
+// the site shows for the logged in user, or ErrSessionInvalid.
+func (session Session) WhoAmI() (string, error) {
+	url := "https://adventofcode.com/"
+	resp, err := DefaultClient().Get(session, url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return "", &StatusError{url, resp.Status}
+	}
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	match := userRegex.FindSubmatch(page)
+	if match == nil {
+		return "", ErrSessionInvalid
+	}
+	return strings.TrimSpace(string(match[1])), nil
+}
@@ -0,0 +1,52 @@
+package utils
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"math/rand"
+	"os"
+	"time"
+)
+
+var generate = flag.Bool("generate", false, "Solve a randomly generated input instead of the real one")
+var seed = flag.Int64("seed", 0, "Seed for -generate, to reproduce an input (default a new seed each run)")
+var size = flag.Int("size", 0, "Size of the input made by -generate, e.g. the number of lines (default about the size of a real input)")
+var saveInput = flag.String("save-input", "", "Write the input made by -generate to this file")
+
+// Generator produces a random input with the same structure as a day's real
+// input. What size means (lines, grid width, ...) is up to the day, as are
+// any limits on it.
+type Generator func(rng *rand.Rand, size int) (string, error)
+
+// Generating reports whether -generate is set.
+func Generating() bool {
+	return *generate
+}
+
+// Generate makes an input with gen, using -seed and -size, or defaultSize if
+// -size isn't set. The seed is always reported on stderr so that an
+// interesting input can be made again. A size the generator can't make a
+// valid input for is fatal.
+func Generate(gen Generator, defaultSize int) string {
+	s := *seed
+	if s == 0 {
+		s = time.Now().UnixNano()
+	}
+	n := *size
+	if n <= 0 {
+		n = defaultSize
+	}
+	fmt.Fprintf(os.Stderr, "generated input with -seed %d -size %d\n", s, n)
+
+	input, err := gen(rand.New(rand.NewSource(s)), n)
+	if err != nil {
+		log.Fatalf("generating input: %v", err)
+	}
+	if *saveInput != "" {
+		if err := os.WriteFile(*saveInput, []byte(input), 0640); err != nil {
+			Log.Error("couldn't save generated input", "path", *saveInput, "error", err)
+		}
+	}
+	return input
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// LeaderboardRefreshInterval is the minimum time between fetches of a
+// private leaderboard, as requested by adventofcode.com.
+const LeaderboardRefreshInterval = 15 * time.Minute
+
+// Leaderboard is a private leaderboard, as served by
+// https://adventofcode.com/<year>/leaderboard/private/view/<id>.json
+type Leaderboard struct {
+	Event   string            `json:"event"`
+	OwnerID int               `json:"owner_id"`
+	Members map[string]Member `json:"members"`
+	// Fetched is when the leaderboard was downloaded (not part of the API)
+	Fetched time.Time `json:"-"`
+}
+
+// Member is one person's entry on a private leaderboard.
+type Member struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Stars       int    `json:"stars"`
+	LocalScore  int    `json:"local_score"`
+	GlobalScore int    `json:"global_score"`
+	LastStarTS  int64  `json:"last_star_ts"`
+	// CompletionDayLevel maps day -> part -> star
+	CompletionDayLevel map[string]map[string]Star `json:"completion_day_level"`
+}
+
+// Star records when a member solved one part of a day.
+type Star struct {
+	GetStarTS int64 `json:"get_star_ts"`
+	StarIndex int   `json:"star_index"`
+}
+
+// DisplayName is the member's name, or a placeholder for anonymous members.
+func (m Member) DisplayName() string {
+	if m.Name == "" {
+		return fmt.Sprintf("(anonymous user #%d)", m.ID)
+	}
+	return m.Name
+}
+
+// StarTime returns when the member got the star for a day's part, if they have.
+func (m Member) StarTime(day, part int) (time.Time, bool) {
+	star, ok := m.CompletionDayLevel[strconv.Itoa(day)][strconv.Itoa(part)]
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(star.GetStarTS, 0), true
+}
+
+// Standings returns the members ordered by local score, then stars, then
+// whoever got their last star first.
+func (l *Leaderboard) Standings() []Member {
+	members := make([]Member, 0, len(l.Members))
+	for _, m := range l.Members {
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if a.LocalScore != b.LocalScore {
+			return a.LocalScore > b.LocalScore
+		}
+		if a.Stars != b.Stars {
+			return a.Stars > b.Stars
+		}
+		return a.LastStarTS < b.LastStarTS
+	})
+	return members
+}
+
+func leaderboardPath(year, id int) string {
+	return filepath.Join(cacheRoot, ".leaderboard", strconv.Itoa(year), strconv.Itoa(id)+".json")
+}
+
+// Leaderboard returns a private leaderboard. A copy is cached locally and
+// reused until it is LeaderboardRefreshInterval old, so calling this often
+// never fetches more often than the site allows. Offline, any cached copy is
+// used.
+func (session Session) Leaderboard(year, id int) (*Leaderboard, error) {
+	path := leaderboardPath(year, id)
+	if info, err := os.Stat(path); err == nil && (*offline || time.Since(info.ModTime()) < LeaderboardRefreshInterval) {
+		Log.Debug("reading leaderboard from cache", "path", path, "age", time.Since(info.ModTime()).Round(time.Second))
+		return readLeaderboard(path, info.ModTime())
+	}
+
+	url := fmt.Sprintf("https://adventofcode.com/%d/leaderboard/private/view/%d.json", year, id)
+	Log.Info("fetching leaderboard", "url", url)
+	resp, err := DefaultClient().Get(session, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return nil, &StatusError{url, resp.Status}
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// an expired session gets redirected to an HTML login page rather than
+	// an error status, so make sure this really is a leaderboard before
+	// caching it
+	var board Leaderboard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("GET %s: response is not a leaderboard (is the session valid?): %w", url, err)
+	}
+	board.Fetched = time.Now()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0640); err != nil {
+		return nil, err
+	}
+	return &board, nil
+}
+
+func readLeaderboard(path string, fetched time.Time) (*Leaderboard, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var board Leaderboard
+	if err := json.Unmarshal(data, &board); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	board.Fetched = fetched
+	return &board, nil
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package utils
+
+import "os"
+
+// lockFile is a no-op where flock isn't available, so concurrent processes
+// may occasionally both send a request inside the minimum interval.
+func lockFile(fh *os.File) error {
+	return nil
+}
+
+func unlockFile(fh *os.File) error {
+	return nil
+}
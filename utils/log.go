@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+)
+
+// logLevel is the minimum level of diagnostics written to stderr. Answers are
+// always printed to stdout, whatever the level.
+var logLevel = new(slog.LevelVar)
+
+// Log is the logger for diagnostics, shared by utils and the days.
+var Log = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+func init() {
+	logLevel.Set(slog.LevelWarn)
+	flag.TextVar(logLevel, "log-level", logLevel, "Minimum level of diagnostics written to stderr (debug, info, warn, error)")
+	flag.BoolFunc("v", "Verbose, same as -log-level=debug", func(string) error {
+		logLevel.Set(slog.LevelDebug)
+		return nil
+	})
+}
@@ -0,0 +1,120 @@
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+// runParallel fans parsing out over s.Workers goroutines and reassembles the
+// results in input order.
+func (s Stream[T]) runParallel(ctx context.Context) <-chan Item[T] {
+	type indexed struct {
+		index int
+		item  Item[T]
+	}
+
+	lines := Lines(s.Input)
+	jobs := make(chan int)
+	results := make(chan indexed)
+	items := make(chan Item[T])
+	// window bounds how far the workers may run ahead of the consumer, which
+	// in turn bounds the size of the reorder buffer
+	window := make(chan struct{}, 4*s.Workers)
+
+	go func() {
+		defer close(jobs)
+		for i := range lines {
+			select {
+			case window <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for w := 0; w < s.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := indexed{i, s.parseLine(lines[i])}
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	go func() {
+		defer close(items)
+		pending := map[int]Item[T]{}
+		next := 0
+		for result := range results {
+			pending[result.index] = result.item
+			for {
+				item, ok := pending[next]
+				if !ok {
+					break
+				}
+				delete(pending, next)
+				select {
+				case items <- item:
+				case <-ctx.Done():
+					return
+				}
+				<-window
+				next++
+			}
+		}
+	}()
+	return items
+}
+
+// ParseAll parses every line of input with the given number of workers and
+// returns the values in input order, stopping at the first parse error.
+func ParseAll[T any](input string, workers int, parse func(line string) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := Stream[T]{Input: input, Parse: parse, Workers: workers}
+	var values []T
+	for item := range stream.Run(ctx) {
+		if item.Err != nil {
+			return nil, item.Err
+		}
+		values = append(values, item.Value)
+	}
+	return values, nil
+}
+
+// ParseAllLenient is like ParseAll but skips lines that fail to parse,
+// returning the values that did parse along with an error for each skipped
+// line.
+func ParseAllLenient[T any](input string, workers int, parse func(line string) (T, error)) ([]T, []*ParseError) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream := Stream[T]{Input: input, Parse: parse, Workers: workers}
+	var values []T
+	var skipped []*ParseError
+	for item := range stream.Run(ctx) {
+		if item.Err != nil {
+			skipped = append(skipped, item.Err.(*ParseError))
+			continue
+		}
+		values = append(values, item.Value)
+	}
+	return values, skipped
+}
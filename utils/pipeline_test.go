@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// benchmarkInput is 10000 lines shaped like day7's hands
+var benchmarkInput = func() string {
+	var sb strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&sb, "%05d %d\n", i, i%1000)
+	}
+	return sb.String()
+}()
+
+// cheapParse does about as much work per line as day7's parser
+func cheapParse(line string) (int, error) {
+	_, bid, _ := strings.Cut(line, " ")
+	return strconv.Atoi(bid)
+}
+
+// expensiveParse stands in for a parser doing real work on every line
+func expensiveParse(line string) (int, error) {
+	sum := []byte(line)
+	for i := 0; i < 200; i++ {
+		s := sha256.Sum256(sum)
+		sum = s[:]
+	}
+	return int(sum[0]), nil
+}
+
+var testWorkers = []int{0, 1, 2, 3, 8, 64}
+
+func TestParseAll(t *testing.T) {
+	want := []int{}
+	for i := 0; i < 10000; i++ {
+		want = append(want, i%1000)
+	}
+	for _, workers := range testWorkers {
+		got, err := ParseAll(benchmarkInput, workers, cheapParse)
+		if err != nil {
+			t.Fatalf("workers=%d: %v", workers, err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("workers=%d: got %d values, want %d", workers, len(got), len(want))
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("workers=%d: value %d is %d, want %d", workers, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestParseAllError(t *testing.T) {
+	// the input starts with a blank line, which still counts towards the
+	// line numbers, and has two bad lines; the first must be reported
+	lines := strings.Split(strings.TrimSpace(benchmarkInput), "\n")
+	lines[4000] = "04000 four"
+	lines[6000] = "06000 six"
+	input := "\n" + strings.Join(lines, "\n")
+
+	for _, workers := range testWorkers {
+		_, err := ParseAll(input, workers, cheapParse)
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Fatalf("workers=%d: got error %v, want a *ParseError", workers, err)
+		}
+		if pe.Line != 4002 || pe.Text != "04000 four" {
+			t.Errorf("workers=%d: error at line %d (%q), want line 4002", workers, pe.Line, pe.Text)
+		}
+	}
+}
+
+func TestStreamCancel(t *testing.T) {
+	for _, workers := range testWorkers {
+		before := runtime.NumGoroutine()
+		ctx, cancel := context.WithCancel(context.Background())
+		items := Stream[int]{Input: benchmarkInput, Parse: cheapParse, Workers: workers}.Run(ctx)
+		for i := 0; i < 10; i++ {
+			<-items
+		}
+		cancel()
+
+		// a few items may already be on their way, but the stream must close
+		// long before the end of the input
+		deadline := time.After(5 * time.Second)
+		drained := 0
+		for open := true; open; {
+			select {
+			case _, open = <-items:
+				drained++
+			case <-deadline:
+				t.Fatalf("workers=%d: stream still open after cancelling", workers)
+			}
+		}
+		if drained > 1000 {
+			t.Errorf("workers=%d: stream delivered %d more items after cancelling", workers, drained)
+		}
+		for runtime.NumGoroutine() > before {
+			select {
+			case <-deadline:
+				t.Fatalf("workers=%d: %d goroutines left running after cancelling", workers, runtime.NumGoroutine()-before)
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+}
+
+func BenchmarkParseAll(b *testing.B) {
+	parsers := []struct {
+		name  string
+		parse func(string) (int, error)
+	}{
+		{"cheap", cheapParse},
+		{"expensive", expensiveParse},
+	}
+	for _, p := range parsers {
+		for _, workers := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("%s/workers=%d", p.name, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := ParseAll(benchmarkInput, workers, p.parse); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package utils
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// Puzzle is the description of a day's puzzle. Part two is only shown once
+// part one has been solved, so Parts may have one or two entries.
+type Puzzle struct {
+	Year, Day int
+	Title     string
+	Parts     []string // Markdown for each visible part
+}
+
+var articleRegex = regexp.MustCompile(`(?s)<article class="day-desc">(.*?)</article>`)
+var titleRegex = regexp.MustCompile(`^## Day \d+: (.*)`)
+
+// Puzzle fetches and converts a day's puzzle description.
+func (session Session) Puzzle(year, day int) (*Puzzle, error) {
+	url := fmt.Sprintf("https://adventofcode.com/%d/day/%d", year, day)
+	Log.Info("fetching puzzle", "url", url)
+	resp, err := DefaultClient().Get(session, url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case 200:
+	case 404:
+		return nil, fmt.Errorf("GET %s: %w", url, ErrNotAvailable)
+	default:
+		return nil, &StatusError{url, resp.Status}
+	}
+	page, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := ParsePuzzle(page)
+	if err != nil {
+		return nil, fmt.Errorf("GET %s: %w", url, err)
+	}
+
+	puzzle := &Puzzle{Year: year, Day: day, Parts: parts}
+	if match := titleRegex.FindStringSubmatch(parts[0]); match != nil {
+		puzzle.Title = match[1]
+	}
+	return puzzle, nil
+}
+
+// ParsePuzzle extracts each <article class="day-desc"> from a puzzle page and
+// converts it to Markdown.
+func ParsePuzzle(page []byte) ([]string, error) {
+	parts := []string{}
+	for _, match := range articleRegex.FindAllSubmatch(page, -1) {
+		markdown, err := HTMLToMarkdown(string(match[1]))
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, markdown)
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("no puzzle description found on page")
+	}
+	return parts, nil
+}
+
+// Markdown renders the whole puzzle as a single document.
+func (p *Puzzle) Markdown() string {
+	return strings.Join(p.Parts, "\n\n") + "\n"
+}
+
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`)
+var whitespaceRegex = regexp.MustCompile(`\s+`)
+var blankLinesRegex = regexp.MustCompile(`\n{3,}`)
+
+// HTMLToMarkdown converts the small subset of HTML used in puzzle
+// descriptions (headings, paragraphs, emphasis, code, lists and links) to
+// Markdown. Other tags are dropped, keeping their text.
+func HTMLToMarkdown(html string) (string, error) {
+	decoder := xml.NewDecoder(strings.NewReader("<root>" + html + "</root>"))
+	decoder.Strict = false
+	decoder.AutoClose = xml.HTMLAutoClose
+	decoder.Entity = xml.HTMLEntity
+
+	var out strings.Builder
+	inPre, inCode := false, false
+	links := []string{} // hrefs of the links currently open
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "h2":
+				out.WriteString("\n\n## ")
+			case "p":
+				out.WriteString("\n\n")
+			case "pre":
+				out.WriteString("\n\n```\n")
+				inPre = true
+			case "code":
+				if !inPre {
+					out.WriteString("`")
+				}
+				inCode = true
+			case "em":
+				if !inCode {
+					out.WriteString("*")
+				}
+			case "ul":
+				out.WriteString("\n")
+			case "li":
+				out.WriteString("\n- ")
+			case "a":
+				href := attr(t, "href")
+				if strings.HasPrefix(href, "/") {
+					href = "https://adventofcode.com" + href
+				}
+				links = append(links, href)
+				out.WriteString("[")
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "h2", "p", "ul":
+				out.WriteString("\n\n")
+			case "pre":
+				if !strings.HasSuffix(out.String(), "\n") {
+					out.WriteString("\n")
+				}
+				out.WriteString("```\n\n")
+				inPre = false
+			case "code":
+				if !inPre {
+					out.WriteString("`")
+				}
+				inCode = false
+			case "em":
+				if !inCode {
+					out.WriteString("*")
+				}
+			case "a":
+				if len(links) > 0 {
+					fmt.Fprintf(&out, "](%s)", links[len(links)-1])
+					links = links[:len(links)-1]
+				}
+			}
+		case xml.CharData:
+			if inPre {
+				out.WriteString(string(t))
+				break
+			}
+			text := whitespaceRegex.ReplaceAllString(string(t), " ")
+			// whitespace between block elements isn't content
+			if out.Len() == 0 || strings.HasSuffix(out.String(), "\n") {
+				text = strings.TrimLeft(text, " ")
+			}
+			if !inCode {
+				text = markdownEscaper.Replace(text)
+			}
+			out.WriteString(text)
+		}
+	}
+
+	markdown := blankLinesRegex.ReplaceAllString(out.String(), "\n\n")
+	// puzzle headings look like "--- Day 1: Trebuchet?! ---"
+	markdown = strings.NewReplacer("## --- ", "## ", " ---\n", "\n").Replace(markdown)
+	return strings.TrimSpace(markdown), nil
+}
+
+func attr(element xml.StartElement, name string) string {
+	for _, a := range element.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,339 @@
+package utils
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var force = flag.Bool("force", false, "Recompute answers even if they are cached for this input and source")
+var timeout = flag.Duration("timeout", time.Minute, "Give up on a part after this long (0 to wait forever)")
+
+// Result is the outcome of running one part of a day's solution.
+type Result struct {
+	Year, Day, Part int
+	Answer          string
+	Duration        time.Duration
+	Computed        time.Time // when the answer was computed
+	Cached          bool      // true if the answer came from the results cache
+	Err             error     // why the part produced no answer, e.g. a timeout
+}
+
+// cachedResult is the on-disk form of a Result.
+type cachedResult struct {
+	Answer      string        `json:"answer"`
+	Duration    time.Duration `json:"duration"`
+	Computed    time.Time     `json:"computed"`
+	Version     string        `json:"version"`  // hash of the solver's source
+	Settings    string        `json:"settings"` // hash of the flags it was run with
+	InputSHA256 string        `json:"input_sha256"`
+}
+
+// Solve runs one part of a day's solution on input and prints its answer.
+//
+// Answers are cached on disk keyed by the solver version (a hash of the
+// calling day's source files and this package's), the flags it was run with
+// and the SHA-256 of the input, so re-running a day whose source, flags and
+// input haven't changed skips the computation. Pass -force to recompute
+// anyway.
+//
+// A part that runs longer than -timeout is reported as timed out rather than
+// waited for, and a part that panics is reported as failed (with its stack
+// trace logged), so the next part still gets to run.
+func Solve[T any](year, day, part int, input string, solve func(input string) T) Result {
+	_, caller, _, _ := runtime.Caller(1)
+	return run(filepath.Dir(caller), year, day, part, input, func(_ context.Context, input string) T {
+		return solve(input)
+	})
+}
+
+// SolveContext is like Solve for solutions that take a context. The context
+// is cancelled when the part times out, so long-running solutions can poll
+// ctx.Done() and give up rather than keep running in the background.
+func SolveContext[T any](year, day, part int, input string, solve func(ctx context.Context, input string) T) Result {
+	_, caller, _, _ := runtime.Caller(1)
+	return run(filepath.Dir(caller), year, day, part, input, solve)
+}
+
+func run[T any](dir string, year, day, part int, input string, solve func(ctx context.Context, input string) T) Result {
+	version := Fingerprint(solverVersion(dir) + solverVersion(utilsDir()))
+	settingsHash := Fingerprint(settings(flag.CommandLine, os.Args[1:]))
+	inputHash := Fingerprint(input)
+	path := resultPath(year, day, part, version, settingsHash, inputHash)
+
+	result := Result{Year: year, Day: day, Part: part}
+	if cached, ok := readResult(path); ok && !*force {
+		result.Answer = cached.Answer
+		result.Duration = cached.Duration
+		result.Computed = cached.Computed
+		result.Cached = true
+		Log.Info("using cached answer", "part", part, "computed", cached.Computed.Format(time.DateTime), "duration", cached.Duration)
+	} else {
+		result.Computed = time.Now()
+		answer, err := runWithTimeout(input, *timeout, solve)
+		result.Duration = time.Since(result.Computed)
+		var pe *PanicError
+		if errors.As(err, &pe) {
+			result.Err = err
+			args := []any{"part", part}
+			if pe.Input != nil {
+				args = append(args, "line", pe.Input.Line, "column", pe.Input.Column, "text", pe.Input.Text)
+			}
+			Log.Error("part panicked", args...)
+			os.Stderr.Write(pe.Stack)
+		} else if err != nil {
+			result.Err = err
+			Log.Info("part failed", "part", part, "duration", result.Duration, "error", err)
+		} else {
+			result.Answer = fmt.Sprint(answer)
+			Log.Info("solved", "part", part, "duration", result.Duration)
+			writeResult(path, cachedResult{result.Answer, result.Duration, result.Computed, version, settingsHash, inputHash})
+		}
+	}
+
+	if result.Err != nil {
+		fmt.Printf("Part %d failed: %v\n", part, result.Err)
+	} else {
+		fmt.Printf("Part %d answer: %s\n", part, result.Answer)
+	}
+	return result
+}
+
+// PanicError reports a part that panicked instead of producing an answer.
+type PanicError struct {
+	Value any
+	Stack []byte
+	// Input is where in the input parsing failed, if the panic was caused
+	// by a parse error
+	Input *ParseError
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// runWithTimeout runs solve on its own goroutine and stops waiting for it
+// once the timeout (if any) expires. A solver that ignores its context keeps
+// running in the background until the process exits. Panics are recovered
+// and returned as a *PanicError.
+func runWithTimeout[T any](input string, limit time.Duration, solve func(ctx context.Context, input string) T) (answer T, err error) {
+	ctx, cancel := context.Background(), context.CancelFunc(func() {})
+	if limit > 0 {
+		ctx, cancel = context.WithTimeout(ctx, limit)
+	}
+	defer cancel()
+
+	done := make(chan T, 1)
+	panicked := make(chan *PanicError, 1)
+	go func() {
+		defer func() {
+			if value := recover(); value != nil {
+				pe := &PanicError{Value: value, Stack: debug.Stack()}
+				if err, ok := value.(error); ok {
+					errors.As(err, &pe.Input)
+				}
+				panicked <- pe
+			}
+		}()
+		done <- solve(ctx, input)
+	}()
+
+	select {
+	case answer = <-done:
+		// a solver that noticed the timeout returns whatever it had so far,
+		// which must not be mistaken for an answer
+		if ctx.Err() != nil {
+			return answer, fmt.Errorf("timed out after %s", limit)
+		}
+		return answer, nil
+	case pe := <-panicked:
+		return answer, pe
+	case <-ctx.Done():
+		return answer, fmt.Errorf("timed out after %s", limit)
+	}
+}
+
+// Fingerprint is the hex SHA-256 of an input.
+func Fingerprint(input string) string {
+	sum := sha256.Sum256([]byte(input))
+	return hex.EncodeToString(sum[:])
+}
+
+var versions sync.Map
+
+// utilsDir is the directory holding this package's source, which every day
+// builds on, so changes to it must invalidate cached answers too.
+func utilsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}
+
+// solverVersion hashes the Go source files in dir, so any edit to a day's
+// solution gives it a new version. If the source isn't available (e.g. a
+// binary run away from its checkout) the executable itself is hashed.
+func solverVersion(dir string) string {
+	if v, ok := versions.Load(dir); ok {
+		return v.(string)
+	}
+
+	h := sha256.New()
+	files, _ := filepath.Glob(filepath.Join(dir, "*.go"))
+	sort.Strings(files)
+	hashed := 0
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00", filepath.Base(file))
+		h.Write(contents)
+		hashed++
+	}
+	if hashed == 0 {
+		if exe, err := os.Executable(); err == nil {
+			if fh, err := os.Open(exe); err == nil {
+				io.Copy(h, fh)
+				fh.Close()
+			}
+		}
+	}
+
+	version := hex.EncodeToString(h.Sum(nil))
+	versions.Store(dir, version)
+	return version
+}
+
+// neutralFlags don't change answers, so they are left out of the cache key.
+var neutralFlags = map[string]bool{
+	"force": true, "timeout": true, "wait": true,
+	"log-level": true, "v": true,
+	"contact": true, "request-timeout": true, "profile": true, "offline": true,
+	"generate": true, "seed": true, "size": true, "save-input": true,
+}
+
+// fileFlags name files whose contents change the answers, see FileFlag.
+var fileFlags = map[string]bool{}
+
+// FileFlag marks a flag as naming input files (comma separated, if there
+// can be more than one), like day2's -bag-file. Their contents become part
+// of the cache key, so editing a file invalidates answers computed from it.
+// Values that aren't files, such as day1's builtin vocabularies, are keyed
+// by name as usual.
+func FileFlag(name string) {
+	fileFlags[name] = true
+}
+
+// flagText records the text each use of a flag was given on the command
+// line. Flags made with flag.Func have no String method worth the name, so
+// their own Value can't tell what they were set to.
+type flagText struct {
+	texts  *[]string
+	isBool bool
+}
+
+func (f flagText) String() string {
+	if f.texts == nil {
+		return ""
+	}
+	return strings.Join(*f.texts, ",")
+}
+
+func (f flagText) Set(s string) error {
+	*f.texts = append(*f.texts, s)
+	return nil
+}
+
+func (f flagText) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// settings describes the flags in fs that were set by args, since options
+// like day2's -bag change the answers. args are parsed again to recover the
+// text each flag was given.
+func settings(fs *flag.FlagSet, args []string) string {
+	given := flag.NewFlagSet(fs.Name(), flag.ContinueOnError)
+	given.SetOutput(io.Discard)
+	fs.VisitAll(func(f *flag.Flag) {
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		given.Var(flagText{&[]string{}, ok && b.IsBoolFlag()}, f.Name, "")
+	})
+	given.Parse(args)
+
+	var b strings.Builder
+	given.Visit(func(f *flag.Flag) {
+		if neutralFlags[f.Name] {
+			return
+		}
+		fmt.Fprintf(&b, "-%s=%q\n", f.Name, *f.Value.(flagText).texts)
+		if !fileFlags[f.Name] {
+			return
+		}
+		for _, path := range strings.Split(f.Value.String(), ",") {
+			if contents, err := os.ReadFile(path); err == nil {
+				fmt.Fprintf(&b, "%s sha256=%s\n", path, Fingerprint(string(contents)))
+			}
+		}
+	})
+	return b.String()
+}
+
+func resultDir(year, day int) string {
+	return filepath.Join(cacheRoot, ".results", fmt.Sprint(year), fmt.Sprint(day))
+}
+
+func resultPath(year, day, part int, version, settingsHash, inputHash string) string {
+	return filepath.Join(resultDir(year, day), fmt.Sprintf("%d-%s-%s-%s.json", part, version[:16], settingsHash[:16], inputHash[:16]))
+}
+
+func readResult(path string) (cachedResult, bool) {
+	var cached cachedResult
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return cached, false
+	}
+	if err := json.Unmarshal(contents, &cached); err != nil {
+		return cached, false
+	}
+	return cached, true
+}
+
+// writeResult stores a result, dropping any results cached for this part by
+// other versions of the solver's source since they can never be used again.
+// Results for other flag settings are kept, so switching back and forth
+// between them doesn't recompute. Failing to cache isn't fatal, the answer
+// just gets recomputed next time.
+func writeResult(path string, result cachedResult) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return
+	}
+	part, _, _ := strings.Cut(filepath.Base(path), "-")
+	stale, _ := filepath.Glob(filepath.Join(dir, part+"-*.json"))
+	for _, old := range stale {
+		if !strings.HasPrefix(filepath.Base(old), fmt.Sprintf("%s-%s-", part, result.Version[:16])) {
+			os.Remove(old)
+		}
+	}
+
+	contents, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return
+	}
+	os.WriteFile(path, contents, 0640)
+}
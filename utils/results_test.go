@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"flag"
+	"testing"
+)
+
+// dayFlags parses args with a flag set shaped like day3's, and returns the
+// settings that would key its cached answers.
+func dayFlags(t *testing.T, args ...string) string {
+	t.Helper()
+	fs := flag.NewFlagSet("day03", flag.ContinueOnError)
+	fs.Bool("example", false, "")
+	fs.Bool("force", false, "")
+	fs.Func("symbols", "", func(string) error { return nil })
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return settings(fs, args)
+}
+
+func TestSettingsKeyFuncFlags(t *testing.T) {
+	star := dayFlags(t, "-example", "-symbols", "*")
+	hash := dayFlags(t, "-example", "-symbols", "#")
+	if star == hash {
+		t.Errorf("-symbols '*' and -symbols '#' share the settings %q", star)
+	}
+	if unset := dayFlags(t, "-example"); unset == star {
+		t.Errorf("-symbols '*' has the same settings as no -symbols: %q", star)
+	}
+	if equals := dayFlags(t, "-example", "-symbols=*"); equals != star {
+		t.Errorf("-symbols=* gives %q, want %q as for -symbols *", equals, star)
+	}
+	if forced := dayFlags(t, "-force", "-example", "-symbols", "*"); forced != star {
+		t.Errorf("-force changed the settings from %q to %q", star, forced)
+	}
+}
@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ParseError reports a failure to parse a single line of input.
+type ParseError struct {
+	Line   int    // 1-based line number within the original input
+	Column int    // 1-based column of the offending fragment, 0 if unknown
+	Text   string // the full line that failed to parse
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 {
+		return fmt.Sprintf("line %d, column %d: %v (in %q)", e.Line, e.Column, e.Err, e.Text)
+	}
+	return fmt.Sprintf("line %d: %v (in %q)", e.Line, e.Err, e.Text)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// AtColumn marks err as having occurred at the given 1-based column of the
+// line being parsed. Stream fills in the line number and text.
+func AtColumn(column int, err error) error {
+	return &ParseError{Column: column, Err: err}
+}
+
+// lineError attaches line information to an error returned by a line parser.
+func lineError(number int, text string, err error) *ParseError {
+	pe := ParseError{Err: err}
+	if e, ok := err.(*ParseError); ok {
+		pe = *e
+	}
+	pe.Line = number
+	pe.Text = text
+	return &pe
+}
+
+// Line is a single line of input along with its 1-based line number.
+type Line struct {
+	Number int
+	Text   string
+}
+
+// Lines splits input into lines the same way the days always have (leading
+// and trailing newlines dropped), but keeps the line numbers of the original
+// input so errors can point at the right place.
+func Lines(input string) []Line {
+	trimmed := strings.TrimLeft(input, "\n")
+	first := len(input) - len(trimmed) + 1
+	trimmed = strings.TrimRight(trimmed, "\n")
+	if trimmed == "" {
+		return nil
+	}
+	texts := strings.Split(trimmed, "\n")
+	lines := make([]Line, len(texts))
+	for i, text := range texts {
+		lines[i] = Line{Number: first + i, Text: text}
+	}
+	return lines
+}
+
+// Item is either a value produced by a Stream or the error that prevented
+// producing it. A non-nil Err is always a *ParseError.
+type Item[T any] struct {
+	Value T
+	Err   error
+	Line  Line // the line the item was parsed from, for reporting problems found later
+}
+
+// Stream parses its input line by line in the background.
+type Stream[T any] struct {
+	Input string
+	Parse func(line string) (T, error)
+	// Workers is the number of goroutines parsing lines concurrently. Values
+	// of 0 or 1 parse sequentially on a single goroutine.
+	Workers int
+}
+
+// parseLine runs s.Parse on a single line, attaching line information to any
+// error. Parsers that panic are reported as errors too, since a panic on one
+// of the stream's goroutines couldn't be recovered by the consumer.
+func (s Stream[T]) parseLine(line Line) (item Item[T]) {
+	defer func() {
+		if value := recover(); value != nil {
+			item = Item[T]{Err: lineError(line.Number, line.Text, fmt.Errorf("panic: %v", value)), Line: line}
+		}
+	}()
+
+	value, err := s.Parse(line.Text)
+	if err != nil {
+		return Item[T]{Err: lineError(line.Number, line.Text, err), Line: line}
+	}
+	return Item[T]{Value: value, Line: line}
+}
+
+// Run starts parsing and returns a channel delivering one Item per line, in
+// input order. Parse errors are delivered as *ParseError items rather than
+// stopping the stream. The channel is closed once the input is exhausted or
+// ctx is cancelled; consumers that stop reading early must cancel ctx so the
+// parsing goroutine can exit.
+func (s Stream[T]) Run(ctx context.Context) <-chan Item[T] {
+	if s.Workers > 1 {
+		return s.runParallel(ctx)
+	}
+
+	items := make(chan Item[T])
+	go func() {
+		defer close(items)
+		for _, line := range Lines(s.Input) {
+			select {
+			case items <- s.parseLine(line):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return items
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+	_ "time/tzdata" // so America/New_York resolves on machines without tzdata
+)
+
+var wait = flag.Bool("wait", false, "If the puzzle hasn't unlocked yet, count down to it and fetch the input as soon as it's available")
+
+// unlockRetries bounds how many times an input is re-requested after the
+// unlock time while the server still says it isn't available (e.g. because
+// our clock is slightly ahead of theirs).
+const unlockRetries = 6
+
+// UnlockTime is when a day's puzzle (and input) becomes available: midnight
+// America/New_York on that day of December.
+func UnlockTime(year, day int) time.Time {
+	eastern, err := time.LoadLocation("America/New_York")
+	panicIf(err)
+	return time.Date(year, time.December, day, 0, 0, 0, 0, eastern)
+}
+
+// FirstEvent is the year Advent of Code started.
+const FirstEvent = 2015
+
+// LatestEvent is the most recent event that has started: this year's from
+// December 1st, otherwise last year's.
+func LatestEvent() int {
+	year := time.Now().In(UnlockTime(FirstEvent, 1).Location()).Year()
+	if time.Now().Before(UnlockTime(year, 1)) {
+		return year - 1
+	}
+	return year
+}
+
+// WaitForUnlock blocks until the puzzle unlocks, showing a countdown on
+// stderr.
+func WaitForUnlock(year, day int) {
+	unlock := UnlockTime(year, day)
+	if time.Until(unlock) <= 0 {
+		return
+	}
+	Log.Info("waiting for puzzle to unlock", "year", year, "day", day, "unlock", unlock.Local())
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for remaining := time.Until(unlock); remaining > 0; remaining = time.Until(unlock) {
+		fmt.Fprintf(os.Stderr, "\r%d day %d unlocks in %s ", year, day, remaining.Round(time.Second))
+		select {
+		case <-ticker.C:
+		case <-time.After(remaining):
+		}
+	}
+	fmt.Fprintf(os.Stderr, "\r%d day %d is unlocked!%20s\n", year, day, "")
+}
+
+// FetchWhenUnlocked waits for the puzzle to unlock and then fetches its
+// input, retrying with increasing delays while the server still says it
+// isn't available.
+func (session Session) FetchWhenUnlocked(year, day int) ([]byte, error) {
+	WaitForUnlock(year, day)
+
+	delay := time.Second
+	for attempt := 1; ; attempt++ {
+		data, err := session.FetchInputs(year, day)
+		if !errors.Is(err, ErrNotAvailable) || attempt > unlockRetries {
+			return data, err
+		}
+		Log.Info("input not available yet, retrying", "attempt", attempt, "delay", delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
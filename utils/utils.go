@@ -1,18 +1,26 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"time"
 )
 
+// cacheRoot is where inputs (and anything else worth keeping between runs)
+// are stored
+const cacheRoot = "/tmp/aoc"
+
 // session id is a github session id (plucked from cookies in browser)
 type Session struct {
 	SessionID string
 	GID       string
+	Expires   time.Time // when the session cookie expires, if known
+	Source    string    // where the session came from, for messages
 }
 
 func panicIf(err error) {
@@ -29,61 +37,103 @@ func AsInt(m string) int {
 	return i
 }
 
-// FetchInputs gets the input data for a given year and day
-func (session Session) FetchInputs(year, day int) []byte {
-	client := &http.Client{}
+// ErrNotAvailable is returned when adventofcode.com doesn't have the input
+// yet, normally because the puzzle hasn't unlocked.
+var ErrNotAvailable = errors.New("puzzle input is not available (yet)")
+
+// StatusError is an unexpected HTTP response from adventofcode.com.
+type StatusError struct {
+	URL    string
+	Status string
+}
 
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: %s", e.URL, e.Status)
+}
+
+// FetchInputs gets the input data for a given year and day
+func (session Session) FetchInputs(year, day int) ([]byte, error) {
+	if year < FirstEvent {
+		return nil, fmt.Errorf("invalid year %d, must be >= %d. Make sure you updated the template", year, FirstEvent)
+	}
 	if day <= 0 {
-		panic("invalid day, must be > 0. Make sure you updated the template.")
+		return nil, fmt.Errorf("invalid day %d, must be > 0. Make sure you updated the template", day)
 	}
-	req, err := http.NewRequest("GET", fmt.Sprintf("https://adventofcode.com/%d/day/%d/input", year, day), nil)
-	fmt.Println("session =", session.SessionID)
-	fmt.Println("gid =", session.GID)
-	panicIf(err)
-	// ...
-	req.AddCookie(
-		&http.Cookie{
-			Name:  "session",
-			Value: session.SessionID,
-		},
-	)
-	req.AddCookie(
-		&http.Cookie{
-			Name:  "_gid",
-			Value: session.GID,
-		},
-	)
-	resp, err := client.Do(req)
-	panicIf(err)
-
-	if resp.StatusCode != 200 {
-		panic(fmt.Errorf("Unhandled response %s", fmt.Sprint(resp)))
+	url := fmt.Sprintf("https://adventofcode.com/%d/day/%d/input", year, day)
+	resp, err := DefaultClient().Get(session, url)
+	if err != nil {
+		return nil, err
 	}
-
 	defer resp.Body.Close()
-	data, err := io.ReadAll(resp.Body)
-	panicIf(err)
-	return data
+
+	// never hand back the body of an error page, it must not end up cached
+	// as if it were the input
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusBadRequest:
+		// the site's answer to requesting an input without being logged in
+		return nil, fmt.Errorf("GET %s: %w", url, ErrSessionInvalid)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("GET %s: %w", url, ErrNotAvailable)
+	default:
+		return nil, &StatusError{url, resp.Status}
+	}
+
+	return io.ReadAll(resp.Body)
 }
 
+// GetInputs returns the input for a given year and day, from the local cache
+// if possible and otherwise from the web. With -wait, a puzzle that hasn't
+// unlocked yet is waited for. Failing to get the input is fatal.
 func GetInputs(year int, day int) string {
-	localRoot := "/tmp/aoc/"
-	localCopy := fmt.Sprintf("%s/%d/%d", localRoot, year, day)
+	input, err := LoadInputs(year, day, *wait)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return input
+}
+
+// InputCached reports whether the input for a given year and day is already
+// in the local cache, so getting it won't touch the network.
+func InputCached(year, day int) bool {
+	_, err := os.Stat(inputPath(year, day))
+	return err == nil
+}
+
+// LoadInputs is like GetInputs, but returns errors rather than panicking and
+// takes whether to wait for the puzzle to unlock as an argument.
+func LoadInputs(year, day int, wait bool) (string, error) {
+	localCopy := inputPath(year, day)
+
+	bytes, err := os.ReadFile(localCopy)
+	if err == nil {
+		Log.Debug("reading input from cache", "path", localCopy)
+		if err := CheckInput(bytes); err != nil {
+			Log.Warn("cached input looks wrong, replace it with aoc cache refetch", "path", localCopy, "problem", err)
+		}
+		return string(bytes), nil
+	}
 
-	fh, err := os.Open(localCopy)
+	if *offline {
+		return "", fmt.Errorf("input for %d day %d isn't cached (fetch it with aoc fetch or copy a cache over with aoc cache import): %w", year, day, ErrOffline)
+	}
+	Log.Info("fetching input from web", "year", year, "day", day)
+	session, err := ResolveSession()
 	if err != nil {
-		fmt.Println("fetching from web")
-		session := Session{SessionID: os.Getenv("AOC_SESSION_ID"), GID: os.Getenv("AOC_GID")}
-		response := session.FetchInputs(year, day)
-		err := os.MkdirAll(fmt.Sprintf("%s/%d", localRoot, year), 0750)
-		panicIf(err)
-		fmt.Println("caching result")
-		os.WriteFile(localCopy, response, 0750)
-		return string(response)
+		return "", err
+	}
+	var response []byte
+	if wait {
+		response, err = session.FetchWhenUnlocked(year, day)
 	} else {
-		fmt.Println("reading from cache")
-		bytes, err := io.ReadAll(fh)
-		panicIf(err)
-		return string(bytes)
+		response, err = session.FetchInputs(year, day)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if err := storeInput(year, day, response); err != nil {
+		return "", err
 	}
+	return string(response), nil
 }